@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/tools"
+)
+
+func runCheckConfig(args []string, stdout, stderr io.Writer) int {
+	fs, cf := newFlagSet("check-config", stderr)
+	verify := fs.Bool("verify", false, "Call the TP API metadata endpoint to verify domain and token")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "Configuration invalid: %v\n", err)
+		return 1
+	}
+	enabled, err := tools.Enabled(cfg.Tools)
+	if err != nil {
+		fmt.Fprintf(stderr, "Configuration invalid: %v\n", err)
+		return 1
+	}
+
+	if cfg.File != "" {
+		fmt.Fprintf(stdout, "Config file:    %s\n", cfg.File)
+	}
+	fmt.Fprintf(stdout, "Instance:       %s (default)\n", cfg.InstanceName)
+	fmt.Fprintf(stdout, "Domain:         %s\n", cfg.Domain)
+	fmt.Fprintf(stdout, "Endpoint:       %s://%s%s\n", cfg.Endpoint.Scheme, cfg.Domain, cfg.Endpoint.BasePath)
+	if cfg.Endpoint.Proxy != "" {
+		fmt.Fprintf(stdout, "Proxy:          %s\n", cfg.Endpoint.Proxy)
+	}
+	if cfg.Endpoint.CAFile != "" {
+		fmt.Fprintf(stdout, "CA file:        %s\n", cfg.Endpoint.CAFile)
+	}
+	if cfg.Endpoint.ClientCert != "" {
+		fmt.Fprintf(stdout, "Client cert:    %s\n", cfg.Endpoint.ClientCert)
+	}
+	fmt.Fprintf(stdout, "Auth method:    %s\n", cfg.Auth.Method)
+	if cfg.Auth.UsesToken() {
+		fmt.Fprintf(stdout, "Access token:   %s\n", describeToken(cfg))
+	} else {
+		fmt.Fprintf(stdout, "Username:       %s\n", cfg.Auth.Username)
+		fmt.Fprintf(stdout, "Password:       %s\n", maskSecret(cfg.Auth.Password))
+	}
+	for _, inst := range cfg.Instances {
+		if cfg.Auth.UsesToken() {
+			fmt.Fprintf(stdout, "Instance:       %s (%s, token %s)\n", inst.Name, inst.Domain, describeToken(cfg.ForInstance(inst)))
+		} else {
+			fmt.Fprintf(stdout, "Instance:       %s (%s, user %s)\n", inst.Name, inst.Domain, inst.Username)
+		}
+	}
+	if cfg.AccessTokenFile != "" || cfg.AccessTokenCommand != "" {
+		fmt.Fprintf(stdout, "Token refresh:  %s\n", cfg.Auth.TokenRefresh)
+	}
+	fmt.Fprintf(stdout, "Max retries:    %d\n", cfg.Retry.MaxRetries)
+	fmt.Fprintf(stdout, "Retry delay:    %s\n", cfg.Retry.InitialDelay)
+	fmt.Fprintf(stdout, "Backoff factor: %g\n", cfg.Retry.BackoffFactor)
+	fmt.Fprintf(stdout, "Rate limit:     %g req/s (burst %d)\n", cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+	fmt.Fprintf(stdout, "Breaker:        %d failures, open %s\n", cfg.Breaker.FailureThreshold, cfg.Breaker.OpenTimeout)
+	if cfg.Cache.Enabled {
+		fmt.Fprintf(stdout, "Cache:          %d entries, TTL entity %s, list %s, metadata %s\n",
+			cfg.Cache.MaxEntries, cfg.Cache.EntityTTL, cfg.Cache.ListTTL, cfg.Cache.MetadataTTL)
+	} else {
+		fmt.Fprintf(stdout, "Cache:          off\n")
+	}
+	fmt.Fprintf(stdout, "HTTP timeout:   %s\n", cfg.Timeouts.HTTP)
+	fmt.Fprintf(stdout, "Tool timeout:   %s\n", cfg.Timeouts.Tool)
+	fmt.Fprintf(stdout, "Max attachment: %d bytes\n", cfg.Limits.MaxAttachmentSize)
+	fmt.Fprintf(stdout, "Max batch:      %d items\n", cfg.Limits.MaxBatchSize)
+	fmt.Fprintf(stdout, "Dry run:        %t\n", cfg.Writes.DryRun)
+	names := make([]string, len(enabled))
+	for i, r := range enabled {
+		names[i] = r.Name
+	}
+	fmt.Fprintf(stdout, "Tools:          %s\n", strings.Join(names, ", "))
+
+	if *verify {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := verifyInstance(ctx, cfg); err != nil {
+			fmt.Fprintf(stderr, "Connectivity check failed: %v\n", err)
+			return 1
+		}
+		for _, inst := range cfg.Instances {
+			if err := verifyInstance(ctx, cfg.ForInstance(inst)); err != nil {
+				fmt.Fprintf(stderr, "Connectivity check failed for instance %s: %v\n", inst.Name, err)
+				return 1
+			}
+		}
+		fmt.Fprintln(stdout, "Connectivity:   OK")
+	}
+
+	fmt.Fprintln(stdout, "Configuration OK")
+	return 0
+}
+
+// verifyInstance fetches metadata from the instance cfg describes
+func verifyInstance(ctx context.Context, cfg *config.Config) error {
+	strategy, err := auth.FromConfig(cfg)
+	if err != nil {
+		return err
+	}
+	c, err := client.NewHTTPClient(cfg, strategy)
+	if err != nil {
+		return err
+	}
+	_, err = c.FetchMetadata(ctx)
+	return err
+}
+
+// describeToken shows where cfg's access token comes from, masking literal tokens
+func describeToken(cfg *config.Config) string {
+	switch {
+	case cfg.AccessTokenFile != "":
+		return "from file " + cfg.AccessTokenFile
+	case cfg.AccessTokenCommand != "":
+		return "from command"
+	}
+	return maskSecret(cfg.AccessToken)
+}
+
+// maskSecret hides all but the last four characters of a secret
+func maskSecret(s string) string {
+	if len(s) <= 4 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
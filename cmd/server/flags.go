@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"time"
+
+	"tp-mcp-go/internal/config"
+)
+
+// configFlags holds command-line overrides for config.Load
+type configFlags struct {
+	configFile    string
+	domain        string
+	accessToken   string
+	authMethod    string
+	maxRetries    int
+	retryDelay    time.Duration
+	backoffFactor float64
+	rateLimit     float64
+	transport     string
+	listenAddr    string
+	bearerToken   string
+
+	sessionTokenHeader  string
+	requireSessionToken bool
+
+	auditLog string
+	dryRun   bool
+	cache    bool
+
+	readOnly   bool
+	allowTools string
+	denyTools  string
+}
+
+// newFlagSet creates a FlagSet for a subcommand with the shared config flags registered
+func newFlagSet(name string, output io.Writer) (*flag.FlagSet, *configFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	f := &configFlags{}
+	fs.StringVar(&f.configFile, "config", "", "YAML or JSON config file (overrides TP_CONFIG); environment variables and flags take precedence over it")
+	fs.StringVar(&f.domain, "domain", "", "Target Process domain (overrides TP_DOMAIN)")
+	fs.StringVar(&f.accessToken, "access-token", "", "Target Process access token (overrides TP_ACCESS_TOKEN; prefer the env var)")
+	fs.StringVar(&f.authMethod, "auth-method", "", "How to authenticate to Target Process: access_token, bearer or basic (overrides TP_AUTH_METHOD, default access_token)")
+	fs.IntVar(&f.maxRetries, "max-retries", 0, "Retries after the first attempt for failed API calls (default 3)")
+	fs.DurationVar(&f.retryDelay, "retry-delay", 0, "Delay before the first retry (default 1s)")
+	fs.Float64Var(&f.backoffFactor, "backoff-factor", 0, "Multiplier applied to the retry delay after each attempt (default 2)")
+	fs.Float64Var(&f.rateLimit, "rate-limit", 0, "Requests per second sent to each TP instance, 0 for no limit (overrides TP_RATE_LIMIT, default 10)")
+	fs.StringVar(&f.transport, "transport", "", "MCP transport: stdio or http (overrides TP_MCP_TRANSPORT)")
+	fs.StringVar(&f.listenAddr, "listen", "", "Listen address for the http transport (overrides TP_MCP_LISTEN_ADDR, default 127.0.0.1:8080)")
+	fs.StringVar(&f.bearerToken, "bearer-token", "", "Bearer token required by the http transport (overrides TP_MCP_BEARER_TOKEN; prefer the env var)")
+	fs.StringVar(&f.sessionTokenHeader, "session-token-header", "", "Header carrying a session's own TP access token (overrides TP_MCP_SESSION_TOKEN_HEADER, default X-TP-Access-Token)")
+	fs.BoolVar(&f.requireSessionToken, "require-session-token", false, "Reject http sessions that do not send their own TP access token (overrides TP_MCP_REQUIRE_SESSION_TOKEN)")
+	fs.StringVar(&f.auditLog, "audit-log", "", "JSONL file recording every create/update/comment (overrides TP_AUDIT_LOG)")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "Make create_entity, update_entity and add_comment return the request they would send instead of sending it (overrides TP_DRY_RUN)")
+	fs.BoolVar(&f.cache, "cache", false, "Cache GET responses from Target Process in memory (overrides TP_CACHE)")
+	fs.BoolVar(&f.readOnly, "read-only", false, "Register only tools that never modify Target Process data (overrides TP_READ_ONLY)")
+	fs.StringVar(&f.allowTools, "allow-tools", "", "Comma-separated tools to register; all others are hidden (overrides TP_TOOLS_ALLOW)")
+	fs.StringVar(&f.denyTools, "deny-tools", "", "Comma-separated tools never to register (overrides TP_TOOLS_DENY)")
+	return fs, f
+}
+
+// load reads the configuration from the --config file, or TP_CONFIG when the
+// flag is not set, with the environment and explicitly set flags on top
+func (f *configFlags) load(fs *flag.FlagSet) (*config.Config, error) {
+	if f.configFile != "" {
+		return config.LoadFile(f.configFile, f.options(fs)...)
+	}
+	return config.Load(f.options(fs)...)
+}
+
+// options returns config options for the flags that were explicitly set,
+// so unset flags never clobber environment values or defaults.
+func (f *configFlags) options(fs *flag.FlagSet) []config.Option {
+	var opts []config.Option
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "domain":
+			opts = append(opts, config.WithDomain(f.domain))
+		case "access-token":
+			opts = append(opts, config.WithAccessToken(f.accessToken))
+		case "auth-method":
+			opts = append(opts, config.WithAuthMethod(f.authMethod))
+		case "max-retries":
+			opts = append(opts, config.WithMaxRetries(f.maxRetries))
+		case "retry-delay":
+			opts = append(opts, config.WithInitialDelay(f.retryDelay))
+		case "backoff-factor":
+			opts = append(opts, config.WithBackoffFactor(f.backoffFactor))
+		case "rate-limit":
+			opts = append(opts, config.WithRateLimit(f.rateLimit))
+		case "transport":
+			opts = append(opts, config.WithTransport(f.transport))
+		case "listen":
+			opts = append(opts, config.WithListenAddr(f.listenAddr))
+		case "bearer-token":
+			opts = append(opts, config.WithBearerToken(f.bearerToken))
+		case "session-token-header":
+			opts = append(opts, config.WithSessionTokenHeader(f.sessionTokenHeader))
+		case "require-session-token":
+			opts = append(opts, config.WithRequireSessionToken(f.requireSessionToken))
+		case "audit-log":
+			opts = append(opts, config.WithAuditLog(f.auditLog))
+		case "dry-run":
+			opts = append(opts, config.WithDryRun(f.dryRun))
+		case "cache":
+			opts = append(opts, config.WithCache(f.cache))
+		case "read-only":
+			opts = append(opts, config.WithReadOnly(f.readOnly))
+		case "allow-tools":
+			opts = append(opts, config.WithAllowedTools(config.SplitList(f.allowTools)))
+		case "deny-tools":
+			opts = append(opts, config.WithDeniedTools(config.SplitList(f.denyTools)))
+		}
+	})
+	return opts
+}
@@ -0,0 +1,60 @@
+// Command server runs the Target Process MCP server.
+//
+// Usage:
+//
+//	server [serve] [flags]     run the MCP server over stdio (default)
+//	server version             print the build version
+//	server check-config [flags] validate configuration and optionally verify connectivity
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches to a subcommand and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	cmd := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "serve":
+		return runServe(args, stderr)
+	case "version":
+		fmt.Fprintf(stdout, "tp-mcp-go %s\n", version)
+		return 0
+	case "check-config":
+		return runCheckConfig(args, stdout, stderr)
+	case "help":
+		printUsage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command: %s\n\n", cmd)
+		printUsage(stderr)
+		return 2
+	}
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage: server <command> [flags]
+
+Commands:
+  serve         Run the MCP server (default when no command is given)
+  version       Print the build version
+  check-config  Validate configuration and optionally verify API connectivity
+  help          Show this message
+
+Run "server <command> -h" for the flags of a command.
+`)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Version(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"version"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), version) {
+		t.Errorf("expected version %q in output, got %q", version, stdout.String())
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"bogus"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "unknown command: bogus") {
+		t.Errorf("expected unknown command message, got %q", stderr.String())
+	}
+}
+
+func TestRun_CheckConfigFlagsOverrideEnv(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "env.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "env-token-1234")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check-config", "--domain", "flag.tpondemand.com", "--max-retries", "1"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "flag.tpondemand.com") {
+		t.Errorf("expected flag domain in output, got %q", out)
+	}
+	if !strings.Contains(out, "Max retries:    1") {
+		t.Errorf("expected overridden max retries in output, got %q", out)
+	}
+	if strings.Contains(out, "env-token-1234") {
+		t.Errorf("expected access token to be masked, got %q", out)
+	}
+}
+
+func TestRun_CheckConfigMissingEnv(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "")
+	t.Setenv("TP_ACCESS_TOKEN", "")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"check-config"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "Configuration invalid") {
+		t.Errorf("expected validation error, got %q", stderr.String())
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	if got := maskSecret("abc"); got != "****" {
+		t.Errorf("maskSecret(short) = %q, want ****", got)
+	}
+	if got := maskSecret("secret-token-9876"); got != "****9876" {
+		t.Errorf("maskSecret(long) = %q, want ****9876", got)
+	}
+}
+
+func TestRun_CheckConfigFile(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "")
+	t.Setenv("TP_ACCESS_TOKEN", "")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "domain: file.tpondemand.com\naccessToken: file-token-5678\ntimeouts:\n  http: 5s\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check-config", "--config", path}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "file.tpondemand.com") {
+		t.Errorf("expected file domain in output, got %q", out)
+	}
+	if !strings.Contains(out, "HTTP timeout:   5s") {
+		t.Errorf("expected file timeout in output, got %q", out)
+	}
+}
+
+func TestRun_CheckConfigReadOnly(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "env.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "env-token-1234")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check-config", "--read-only", "--deny-tools", "download_attachment"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, hidden := range []string{"create_entity", "update_entity", "add_comment", "download_attachment"} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("expected %s to be disabled, got %q", hidden, out)
+		}
+	}
+	if !strings.Contains(out, "search") {
+		t.Errorf("expected search to stay enabled, got %q", out)
+	}
+}
+
+func TestRun_CheckConfigUnknownTool(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "env.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "env-token-1234")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"check-config", "--allow-tools", "serach"}, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), `unknown tool "serach"`) {
+		t.Errorf("expected unknown tool error, got %q", stderr.String())
+	}
+}
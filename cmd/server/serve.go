@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"tp-mcp-go/internal/app"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/docs"
+	"tp-mcp-go/internal/tools"
+	"tp-mcp-go/internal/transport"
+
+	foxyapp "github.com/strowk/foxy-contexts/pkg/app"
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+	"github.com/strowk/foxy-contexts/pkg/server"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+)
+
+func runServe(args []string, stderr io.Writer) int {
+	fs, cf := newFlagSet("serve", stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := cf.load(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	b, err := newBuilder(cfg, newTransport(cfg))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := b.Run(); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newTransport selects the MCP transport named by cfg.Transport
+func newTransport(cfg *config.Config) server.Transport {
+	if cfg.Transport == config.TransportHTTP {
+		return transport.NewHTTPTransport(transport.HTTPOptions{
+			Addr:               cfg.HTTP.ListenAddr,
+			BearerToken:        cfg.HTTP.BearerToken,
+			SessionIdleTimeout: cfg.HTTP.SessionIdleTimeout,
+			SessionContext:     app.SessionClient(cfg),
+		})
+	}
+	return transport.NewStdioTransport()
+}
+
+// newBuilder registers the tools allowed by cfg.Tools and every documentation
+// resource on a foxy-contexts app
+func newBuilder(cfg *config.Config, t server.Transport) (*foxyapp.Builder, error) {
+	enabled, err := tools.Enabled(cfg.Tools)
+	if err != nil {
+		return nil, err
+	}
+
+	b := foxyapp.NewBuilder().
+		WithName("tp-mcp-go").
+		WithVersion(version).
+		WithServerCapabilities(&mcp.ServerCapabilities{
+			Tools:     &mcp.ServerCapabilitiesTools{ListChanged: ptr(false)},
+			Resources: &mcp.ServerCapabilitiesResources{ListChanged: ptr(false), Subscribe: ptr(false)},
+		})
+	for _, r := range enabled {
+		b = b.WithTool(r.Constructor)
+	}
+
+	for _, r := range docs.Resources() {
+		resource := r // capture for closure
+		b = b.WithResource(func() fxctx.Resource { return resource })
+	}
+
+	opts := []fx.Option{
+		fx.Supply(cfg),
+		fx.Supply(fx.Annotate(t, fx.As(new(server.Transport)))),
+		app.Module,
+		// stdout carries the MCP protocol, so keep fx quiet
+		fx.WithLogger(func() fxevent.Logger { return fxevent.NopLogger }),
+	}
+	if tt, ok := t.(interface{ UseTools([]fxctx.Tool) }); ok {
+		// Let the transport call tools with each session's context, so the
+		// client router can pick that session's credentials and
+		// notifications/cancelled can stop a call
+		opts = append(opts, fx.Invoke(fx.Annotate(tt.UseTools, fx.ParamTags(`group:"tools"`))))
+	}
+
+	return b.
+		WithTransport(t).
+		WithFxOptions(opts...), nil
+}
+
+// ptr returns a pointer to the given value
+func ptr[T any](v T) *T {
+	return &v
+}
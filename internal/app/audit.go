@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+
+	"go.uber.org/fx"
+	"tp-mcp-go/internal/audit"
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+)
+
+// instanceAuth holds the auth strategies of the configured instances
+type instanceAuth []auth.Strategy
+
+// decorateWithAudit records the client's mutating calls when cfg.Audit.Path is set
+func decorateWithAudit(lc fx.Lifecycle, cfg *config.Config, strategies instanceAuth, c client.Client) (client.Client, error) {
+	if cfg.Audit.Path == "" {
+		return c, nil
+	}
+
+	// Tokens read from a file or command are looked up for every entry, as
+	// they may have been rotated since startup
+	secrets := func() []string {
+		secrets := []string{cfg.AccessToken, cfg.Auth.Password, cfg.HTTP.BearerToken}
+		for _, inst := range cfg.Instances {
+			secrets = append(secrets, inst.AccessToken, inst.Password)
+		}
+		for _, s := range strategies {
+			if r, ok := s.(auth.Rotator); ok {
+				secrets = append(secrets, r.Token())
+			}
+		}
+		return secrets
+	}
+	log, err := audit.Open(cfg.Audit, secrets)
+	if err != nil {
+		return nil, err
+	}
+	// Registered before the transport's hook, so it runs after in-flight
+	// requests have drained
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return log.Close()
+		},
+	})
+	return audit.WrapClient(c, log), nil
+}
@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/fx"
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+)
+
+// newAuthStrategy creates the auth.Strategy for cfg's instance. A token read
+// from a file or command is re-read every cfg.Auth.TokenRefresh while the
+// app runs, besides whenever TP rejects it.
+func newAuthStrategy(lc fx.Lifecycle, cfg *config.Config) (auth.Strategy, error) {
+	strategy, err := auth.FromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("instance %s: %w", cfg.Domain, err)
+	}
+	rotator, ok := strategy.(auth.Rotator)
+	if !ok || cfg.Auth.TokenRefresh <= 0 {
+		return strategy, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go auth.RefreshEvery(ctx, rotator, cfg.Auth.TokenRefresh, func(err error) {
+				fmt.Fprintf(os.Stderr, "Warning: failed to refresh access token for %s: %v\n", cfg.Domain, err)
+			})
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return strategy, nil
+}
@@ -5,24 +5,54 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/server"
 	"go.uber.org/fx"
 	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/tools"
 )
 
+// LifecycleParams are the dependencies of RegisterLifecycleHooks
+type LifecycleParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Client    client.Client
+	Transport server.Transport `optional:"true"`
+	Tools     []fxctx.Tool     `group:"tools"`
+}
+
 // RegisterLifecycleHooks registers fx lifecycle hooks
-func RegisterLifecycleHooks(lc fx.Lifecycle, c client.Client) {
-	lc.Append(fx.Hook{
+func RegisterLifecycleHooks(p LifecycleParams) {
+	// Cancelled on stop so a slow metadata fetch or tool call does not hold up shutdown
+	bgCtx, cancel := context.WithCancel(context.Background())
+	tools.Bind(p.Tools, tools.CallOptions{Base: bgCtx, Timeout: p.Config.Timeouts.Tool})
+
+	p.Lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			// Without a shared token only per-session clients can reach TP
+			if !p.Config.HasCredentials() {
+				return nil
+			}
 			// Launch cache initialization in background (non-blocking)
 			go func() {
-				if err := c.InitializeCache(context.Background()); err != nil {
+				if err := p.Client.InitializeCache(bgCtx); err != nil && bgCtx.Err() == nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to initialize entity type cache: %v\n", err)
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			// Placeholder for future cleanup
+			cancel()
+			// Stop accepting sessions and drain in-flight requests before the
+			// process exits. Transports tolerate repeated Shutdown calls.
+			if p.Transport != nil {
+				if err := p.Transport.Shutdown(ctx); err != nil {
+					return fmt.Errorf("transport shutdown: %w", err)
+				}
+			}
 			return nil
 		},
 	})
@@ -1,19 +1,61 @@
 package app
 
 import (
+	"fmt"
+
 	"go.uber.org/fx"
 	"tp-mcp-go/internal/client"
 	"tp-mcp-go/internal/client/auth"
 	"tp-mcp-go/internal/config"
 )
 
+// Module wires the TP client and lifecycle hooks.
+// The *config.Config is supplied by the caller (see cmd/server).
+// The client routes each call to the instance its context names, and calls
+// for the default instance to the session's own client when the context
+// carries one (see SessionClient). Mutating calls are audited when an
+// audit log is configured.
 var Module = fx.Options(
-	fx.Provide(config.Load),
-	fx.Provide(func(cfg *config.Config) auth.Strategy {
-		return auth.NewAccessTokenStrategy(cfg.AccessToken)
+	fx.Provide(newAuthStrategy),
+	fx.Provide(func(lc fx.Lifecycle, cfg *config.Config, authStrategy auth.Strategy) (client.Client, instanceAuth, error) {
+		defClient, err := client.NewHTTPClient(cfg, authStrategy)
+		if err != nil {
+			return nil, nil, err
+		}
+		strategies := instanceAuth{authStrategy}
+		def := client.Instance{
+			Name:   cfg.InstanceName,
+			Domain: cfg.Domain,
+			Client: defClient,
+		}
+		others := make([]client.Instance, 0, len(cfg.Instances))
+		for _, inst := range cfg.Instances {
+			instCfg := cfg.ForInstance(inst)
+			instAuth, err := newAuthStrategy(lc, instCfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			strategies = append(strategies, instAuth)
+			instClient, err := client.NewHTTPClient(instCfg, instAuth)
+			if err != nil {
+				return nil, nil, fmt.Errorf("instance %s: %w", inst.Name, err)
+			}
+			others = append(others, client.Instance{
+				Name:   inst.Name,
+				Domain: inst.Domain,
+				Client: instClient,
+			})
+		}
+		return client.NewContextRouter(def, others...), strategies, nil
+	}),
+	fx.Decorate(decorateWithAudit),
+	// Tools take their page sizes and size caps from the config
+	fx.Provide(func(cfg *config.Config) config.LimitsConfig {
+		return cfg.Limits
 	}),
-	fx.Provide(func(cfg *config.Config, authStrategy auth.Strategy) client.Client {
-		return client.NewHTTPClient(cfg, authStrategy)
+	// Write tools take the global dry-run switch from the config
+	fx.Provide(func(cfg *config.Config) config.WritesConfig {
+		return cfg.Writes
 	}),
 	fx.Invoke(RegisterLifecycleHooks),
 )
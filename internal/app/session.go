@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"tp-mcp-go/internal/audit"
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+)
+
+// SessionClient returns a transport.HTTPOptions.SessionContext hook that gives
+// each HTTP session its own TP client when the client sends its access token in
+// cfg.HTTP.SessionTokenHeader. Sessions without the header use the shared
+// cfg.AccessToken client, unless cfg.HTTP.RequireSessionToken is set.
+func SessionClient(cfg *config.Config) func(ctx context.Context, r *http.Request) (context.Context, error) {
+	return func(ctx context.Context, r *http.Request) (context.Context, error) {
+		token := r.Header.Get(cfg.HTTP.SessionTokenHeader)
+		if token == "" {
+			if cfg.HTTP.RequireSessionToken {
+				return nil, fmt.Errorf("%s header is required", cfg.HTTP.SessionTokenHeader)
+			}
+			return ctx, nil
+		}
+
+		sessionCfg := *cfg
+		sessionCfg.AccessToken = token
+		// A session token replaces the shared Basic credentials, and is sent as
+		// an access_token parameter in their place
+		c, err := client.NewHTTPClient(&sessionCfg, auth.ForToken(cfg.Auth.Method, token))
+		if err != nil {
+			return nil, err
+		}
+		return audit.WithSecret(client.WithClient(ctx, c), token), nil
+	}
+}
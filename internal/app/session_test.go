@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+)
+
+func sessionConfig(require bool) *config.Config {
+	return &config.Config{
+		Domain:      "test.tpondemand.com",
+		AccessToken: "shared-token",
+		HTTP: config.HTTPConfig{
+			SessionTokenHeader:  config.DefaultSessionTokenHeader,
+			RequireSessionToken: require,
+		},
+	}
+}
+
+func TestSessionClient_WithHeader(t *testing.T) {
+	hook := SessionClient(sessionConfig(false))
+	r := httptest.NewRequest("POST", "/mcp", nil)
+	r.Header.Set(config.DefaultSessionTokenHeader, "alice-token")
+
+	ctx, err := hook(context.Background(), r)
+	if err != nil {
+		t.Fatalf("hook returned unexpected error: %v", err)
+	}
+	if _, ok := client.FromContext(ctx); !ok {
+		t.Error("expected a per-session client on the context")
+	}
+}
+
+func TestSessionClient_WithoutHeader(t *testing.T) {
+	hook := SessionClient(sessionConfig(false))
+
+	ctx, err := hook(context.Background(), httptest.NewRequest("POST", "/mcp", nil))
+	if err != nil {
+		t.Fatalf("hook returned unexpected error: %v", err)
+	}
+	if _, ok := client.FromContext(ctx); ok {
+		t.Error("expected the shared client to be used when no header is sent")
+	}
+}
+
+func TestSessionClient_RequiredHeaderMissing(t *testing.T) {
+	hook := SessionClient(sessionConfig(true))
+
+	if _, err := hook(context.Background(), httptest.NewRequest("POST", "/mcp", nil)); err == nil {
+		t.Fatal("expected error when the session token header is required but missing")
+	}
+}
@@ -0,0 +1,173 @@
+// Package audit records mutating Target Process operations to an append-only
+// JSONL file.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/errors"
+)
+
+// Entry is one line of the audit log
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Tool       string    `json:"tool,omitempty"`
+	Instance   string    `json:"instance,omitempty"`
+	Operation  string    `json:"operation"`
+	EntityType string    `json:"entityType"`
+	EntityID   int       `json:"entityId,omitempty"`
+	Request    any       `json:"request,omitempty"`
+	Before     any       `json:"before,omitempty"`
+	After      any       `json:"after,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type toolKey struct{}
+
+type secretsKey struct{}
+
+// WithTool returns a context whose audit entries name tool as their origin
+func WithTool(ctx context.Context, tool string) context.Context {
+	return context.WithValue(ctx, toolKey{}, tool)
+}
+
+// WithSecret returns a context whose audit entries mask secret, e.g. the
+// access token of an HTTP session
+func WithSecret(ctx context.Context, secret string) context.Context {
+	secrets, _ := ctx.Value(secretsKey{}).([]string)
+	return context.WithValue(ctx, secretsKey{}, append(secrets[:len(secrets):len(secrets)], secret))
+}
+
+// Logger appends entries to a JSONL file, rotating it once it grows past the
+// configured size. It is safe for concurrent use.
+type Logger struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	secrets    func() []string
+
+	file *os.File
+	size int64
+}
+
+// Open opens (or creates) the audit log at cfg.Path. Every secret secrets
+// returns, typically the access tokens in use, is masked in the entries
+// written. It is called for each entry, so tokens rotated since Open are
+// masked too.
+func Open(cfg config.AuditConfig, secrets func() []string) (*Logger, error) {
+	l := &Logger{
+		path:       cfg.Path,
+		maxSize:    cfg.MaxSize,
+		maxBackups: cfg.MaxBackups,
+		secrets:    secrets,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// Record appends e, filling in the time and the tool and instance carried by ctx
+func (l *Logger) Record(ctx context.Context, e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Tool == "" {
+		e.Tool, _ = ctx.Value(toolKey{}).(string)
+	}
+	if e.Instance == "" {
+		e.Instance, _ = client.InstanceFromContext(ctx)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line := string(data)
+	ctxSecrets, _ := ctx.Value(secretsKey{}).([]string)
+	for _, secret := range append(l.secrets(), ctxSecrets...) {
+		line = errors.MaskToken(line, secret)
+	}
+	line += "\n"
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	var rotateErr error
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if rotateErr = l.rotate(); rotateErr != nil && l.file == nil {
+			// Keep appending to whatever file is at path, past maxSize,
+			// rather than losing this entry and every later one
+			if err := l.open(); err != nil {
+				return stderrors.Join(rotateErr, err)
+			}
+		}
+	}
+	n, err := l.file.WriteString(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	// The entry is written, but the caller still hears that rotation failed
+	return rotateErr
+}
+
+// rotate renames the current file to path.1, shifting older backups up and
+// dropping those beyond maxBackups, then starts a new file. At least the
+// file just rotated is kept, even with maxBackups 0.
+func (l *Logger) rotate() error {
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	for i := max(l.maxBackups, 1) - 1; i >= 1; i-- {
+		old := fmt.Sprintf("%s.%d", l.path, i)
+		if err := os.Rename(old, fmt.Sprintf("%s.%d", l.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return l.open()
+}
+
+// Close closes the log file; later Record calls fail
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+)
+
+func openTestLogger(t *testing.T, cfg config.AuditConfig, secrets ...string) *Logger {
+	t.Helper()
+	if cfg.Path == "" {
+		cfg.Path = filepath.Join(t.TempDir(), "audit.jsonl")
+	}
+	if cfg.MaxSize == 0 {
+		cfg.MaxSize = 1 << 20
+	}
+	l, err := Open(cfg, func() []string { return secrets })
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+// readEntries decodes every line of the log at path
+func readEntries(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestRecord_WritesContextAndMasksSecrets(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{}, "configured-token")
+
+	ctx := WithTool(context.Background(), "update_entity")
+	ctx = client.WithInstance(ctx, "sandbox")
+	ctx = WithSecret(ctx, "session-token")
+	err := l.Record(ctx, Entry{
+		Operation:  "update",
+		EntityType: "UserStory",
+		EntityID:   42,
+		Request:    map[string]any{"Description": "uses configured-token and session-token"},
+	})
+	if err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+
+	entries := readEntries(t, l.path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e["tool"] != "update_entity" || e["instance"] != "sandbox" || e["entityId"] != float64(42) {
+		t.Errorf("unexpected entry: %v", e)
+	}
+	if _, ok := e["time"]; !ok {
+		t.Error("expected entry to carry a timestamp")
+	}
+	data, _ := os.ReadFile(l.path)
+	if strings.Contains(string(data), "configured-token") || strings.Contains(string(data), "session-token") {
+		t.Errorf("expected tokens to be masked, got %s", data)
+	}
+}
+
+func TestRecord_MasksTheCurrentSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	token := "first-token"
+	l, err := Open(config.AuditConfig{Path: path, MaxSize: 1 << 20}, func() []string { return []string{token} })
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	token = "rotated-token"
+	if err := l.Record(context.Background(), Entry{Operation: "update", EntityType: "Bug", Error: "rejected rotated-token"}); err != nil {
+		t.Fatalf("Record() unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "rotated-token") {
+		t.Errorf("expected the token rotated in after Open to be masked, got %s", data)
+	}
+}
+
+func TestRecord_Rotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := openTestLogger(t, config.AuditConfig{Path: path, MaxSize: 200, MaxBackups: 2})
+
+	for i := 0; i < 10; i++ {
+		if err := l.Record(context.Background(), Entry{Operation: "create", EntityType: "Bug", EntityID: i + 1}); err != nil {
+			t.Fatalf("Record() unexpected error: %v", err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("expected %s to exist: %v", name, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s is %d bytes, want at most 200", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected at most 2 backups, found %s.3", path)
+	}
+
+	entries := readEntries(t, path)
+	if last := entries[len(entries)-1]; last["entityId"] != float64(10) {
+		t.Errorf("expected newest entry in the current file, got %v", last)
+	}
+}
+
+func TestRecord_RotatesWithoutBackupsConfigured(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := openTestLogger(t, config.AuditConfig{Path: path, MaxSize: 200, MaxBackups: 0})
+
+	for i := 0; i < 10; i++ {
+		if err := l.Record(context.Background(), Entry{Operation: "create", EntityType: "Bug", EntityID: i + 1}); err != nil {
+			t.Fatalf("Record() unexpected error: %v", err)
+		}
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("expected the rotated file to be kept: %v", err)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("expected a single backup, found %s.2", path)
+	}
+}
+
+func TestRecord_KeepsLoggingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l := openTestLogger(t, config.AuditConfig{Path: path, MaxSize: 200, MaxBackups: 1})
+
+	// A non-empty directory in the backup's place makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	var rotateErrs int
+	for i := 0; i < 10; i++ {
+		if err := l.Record(context.Background(), Entry{Operation: "create", EntityType: "Bug", EntityID: i + 1}); err != nil {
+			if !strings.Contains(err.Error(), "failed to rotate audit log") {
+				t.Fatalf("Record() unexpected error: %v", err)
+			}
+			rotateErrs++
+		}
+	}
+	if rotateErrs == 0 {
+		t.Fatal("expected the rotation failure to be reported")
+	}
+	if entries := readEntries(t, path); len(entries) != 10 {
+		t.Errorf("expected every entry to be written to the current file, got %d", len(entries))
+	}
+}
+
+func TestRecord_AfterClose(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{})
+	l.Close()
+	if err := l.Record(context.Background(), Entry{Operation: "create", EntityType: "Bug"}); err == nil {
+		t.Fatal("expected error when recording to a closed log")
+	}
+}
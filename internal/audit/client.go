@@ -0,0 +1,154 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// auditingClient records every mutating call of the Client it wraps
+type auditingClient struct {
+	client.Client
+	log *Logger
+}
+
+// WrapClient returns a Client that records CreateEntity, UpdateEntity,
+// DeleteEntity, CreateComment and bulk calls to log, including a before
+// snapshot for single updates and deletes. A bulk call is recorded as one entry per item.
+// Failing to write an entry is reported on stderr but does not fail the call.
+func WrapClient(c client.Client, log *Logger) client.Client {
+	return &auditingClient{Client: c, log: log}
+}
+
+func (c *auditingClient) record(ctx context.Context, e Entry, err error) {
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if werr := c.log.Record(ctx, e); werr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", werr)
+	}
+}
+
+func (c *auditingClient) CreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
+	result, err := c.Client.CreateEntity(ctx, entityType, data)
+	c.record(ctx, Entry{
+		Operation:  "create",
+		EntityType: string(entityType),
+		EntityID:   entityID(result),
+		Request:    data,
+		After:      result,
+	}, err)
+	return result, err
+}
+
+func (c *auditingClient) UpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+	e := Entry{
+		Operation:  "update",
+		EntityType: string(entityType),
+		EntityID:   id,
+		Request:    data,
+	}
+	// A missing snapshot should not block the update it describes
+	if before, err := c.Client.GetEntity(client.WithoutCache(ctx), entityType, id, nil); err == nil {
+		e.Before = before
+	}
+
+	result, err := c.Client.UpdateEntity(ctx, entityType, id, data)
+	e.After = result
+	c.record(ctx, e, err)
+	return result, err
+}
+
+func (c *auditingClient) DeleteEntity(ctx context.Context, entityType entity.Type, id int) error {
+	e := Entry{
+		Operation:  "delete",
+		EntityType: string(entityType),
+		EntityID:   id,
+	}
+	// TP's default fields, which leave out collections such as assigned users
+	if before, err := c.Client.GetEntity(client.WithoutCache(ctx), entityType, id, nil); err == nil {
+		e.Before = before
+	}
+
+	err := c.Client.DeleteEntity(ctx, entityType, id)
+	c.record(ctx, e, err)
+	return err
+}
+
+func (c *auditingClient) BulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+	results, err := c.Client.BulkCreate(ctx, entityType, items)
+	c.recordBulk(ctx, "create", entityType, items, results, err)
+	return results, err
+}
+
+func (c *auditingClient) BulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+	results, err := c.Client.BulkUpdate(ctx, entityType, items)
+	c.recordBulk(ctx, "update", entityType, items, results, err)
+	return results, err
+}
+
+// recordBulk records one entry per item of a bulk call, carrying the item's
+// own failure, or err when the call failed as a whole
+func (c *auditingClient) recordBulk(ctx context.Context, op string, entityType entity.Type, items []map[string]any, results []client.BulkResult, err error) {
+	for i, item := range items {
+		e := Entry{
+			Operation:  op,
+			EntityType: string(entityType),
+			EntityID:   entityID(item),
+			Request:    item,
+		}
+		itemErr := err
+		if i < len(results) {
+			if results[i].Success {
+				e.EntityID = entityID(results[i].Entity)
+				e.After = results[i].Entity
+			} else {
+				itemErr = errors.New(results[i].Error)
+			}
+		}
+		c.record(ctx, e, itemErr)
+	}
+}
+
+func (c *auditingClient) CreateComment(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
+	comment, err := c.Client.CreateComment(ctx, entityID, description)
+	e := Entry{
+		Operation:  "create",
+		EntityType: "Comment",
+		Request:    map[string]any{"entityId": entityID, "description": description},
+	}
+	if comment != nil {
+		e.EntityID = comment.ID
+		e.After = comment
+	}
+	c.record(ctx, e, err)
+	return comment, err
+}
+
+// Instances forwards to the wrapped client so inspect_object can still list instances
+func (c *auditingClient) Instances() []client.InstanceInfo {
+	if lister, ok := c.Client.(client.InstanceLister); ok {
+		return lister.Instances()
+	}
+	return nil
+}
+
+// Diagnostics forwards to the wrapped client so the diagnostics tool keeps working
+func (c *auditingClient) Diagnostics() []client.Diagnostics {
+	if reporter, ok := c.Client.(client.DiagnosticsReporter); ok {
+		return reporter.Diagnostics()
+	}
+	return nil
+}
+
+// entityID reads the Id of an entity returned by the TP API
+func entityID(result map[string]any) int {
+	if id, ok := result["Id"].(float64); ok {
+		return int(id)
+	}
+	return 0
+}
@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/testutil"
+)
+
+func TestWrapClient_UpdateRecordsBeforeAndAfter(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{})
+	mock := &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{"Id": float64(id), "Name": "Old name"}, nil
+		},
+		UpdateEntityFn: func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+			return map[string]any{"Id": float64(id), "Name": data["Name"]}, nil
+		},
+	}
+	c := WrapClient(mock, l)
+
+	if _, err := c.UpdateEntity(context.Background(), entity.TypeUserStory, 7, map[string]any{"Name": "New name"}); err != nil {
+		t.Fatalf("UpdateEntity returned unexpected error: %v", err)
+	}
+
+	entries := readEntries(t, l.path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e["operation"] != "update" || e["entityType"] != "UserStory" || e["entityId"] != float64(7) {
+		t.Errorf("unexpected entry: %v", e)
+	}
+	if before := e["before"].(map[string]any); before["Name"] != "Old name" {
+		t.Errorf("expected before snapshot, got %v", e["before"])
+	}
+	if after := e["after"].(map[string]any); after["Name"] != "New name" {
+		t.Errorf("expected after snapshot, got %v", e["after"])
+	}
+}
+
+func TestWrapClient_RecordsFailures(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{})
+	mock := &testutil.MockClient{
+		CreateEntityFn: func(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
+			return nil, errors.New("permission denied")
+		},
+	}
+	c := WrapClient(mock, l)
+
+	if _, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Name": "Crash"}); err == nil {
+		t.Fatal("expected the client error to be returned")
+	}
+
+	entries := readEntries(t, l.path)
+	if len(entries) != 1 || entries[0]["error"] != "permission denied" {
+		t.Errorf("expected failed create to be recorded, got %v", entries)
+	}
+}
+
+func TestWrapClient_BulkRecordsEachItem(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{})
+	mock := &testutil.MockClient{
+		BulkCreateFn: func(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+			return []client.BulkResult{
+				{Index: 0, Success: true, Entity: map[string]any{"Id": float64(11), "Name": "First"}},
+				{Index: 1, Error: "Name is too long"},
+			}, nil
+		},
+	}
+	c := WrapClient(mock, l)
+
+	items := []map[string]any{{"Name": "First"}, {"Name": "Second"}}
+	if _, err := c.BulkCreate(context.Background(), entity.TypeTask, items); err != nil {
+		t.Fatalf("BulkCreate returned unexpected error: %v", err)
+	}
+
+	entries := readEntries(t, l.path)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0]["entityId"] != float64(11) || entries[0]["error"] != nil {
+		t.Errorf("unexpected entry for created item: %v", entries[0])
+	}
+	if entries[1]["error"] != "Name is too long" {
+		t.Errorf("expected item failure to be recorded, got %v", entries[1])
+	}
+}
+
+func TestWrapClient_ReadsAreNotRecorded(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{})
+	c := WrapClient(&testutil.MockClient{}, l)
+
+	c.GetEntity(context.Background(), entity.TypeBug, 1, nil)
+	c.ListComments(context.Background(), 1, 10, nil)
+
+	if entries := readEntries(t, l.path); len(entries) != 0 {
+		t.Errorf("expected no entries for reads, got %v", entries)
+	}
+}
+
+func TestWrapClient_ForwardsInstances(t *testing.T) {
+	router := client.NewContextRouter(client.Instance{Name: "prod", Client: &testutil.MockClient{}})
+	c := WrapClient(router, openTestLogger(t, config.AuditConfig{}))
+
+	lister, ok := c.(client.InstanceLister)
+	if !ok || len(lister.Instances()) != 1 {
+		t.Error("expected the audited client to list the router's instances")
+	}
+}
+
+func TestWrapClient_DeleteRecordsSnapshot(t *testing.T) {
+	l := openTestLogger(t, config.AuditConfig{})
+	mock := &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{"Id": float64(id), "Name": "Obsolete"}, nil
+		},
+	}
+	c := WrapClient(mock, l)
+
+	if err := c.DeleteEntity(context.Background(), entity.TypeBug, 9); err != nil {
+		t.Fatalf("DeleteEntity returned unexpected error: %v", err)
+	}
+
+	entries := readEntries(t, l.path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e["operation"] != "delete" || e["entityType"] != "Bug" || e["entityId"] != float64(9) {
+		t.Errorf("unexpected entry: %v", e)
+	}
+	if before := e["before"].(map[string]any); before["Name"] != "Obsolete" {
+		t.Errorf("expected before snapshot, got %v", e["before"])
+	}
+}
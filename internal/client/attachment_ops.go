@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/errors"
 )
 
 // ListAttachments lists attachments for an entity
@@ -16,7 +18,7 @@ func (c *httpClient) ListAttachments(ctx context.Context, entityID int, take int
 	url := fmt.Sprintf("%s/Attachments?where=General.Id eq %d&take=%d",
 		c.baseURL, entityID, take)
 
-	data, err := c.doGet(ctx, url)
+	data, err := c.cachedGet(ctx, url, c.cacheTTL.ListTTL, entityTag(entityID))
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +46,7 @@ func (c *httpClient) ListAttachments(ctx context.Context, entityID int, take int
 // GetAttachmentMetadata retrieves metadata for a single attachment
 func (c *httpClient) GetAttachmentMetadata(ctx context.Context, attachmentID int) (*entity.Attachment, error) {
 	url := fmt.Sprintf("%s/Attachments/%d", c.baseURL, attachmentID)
-	data, err := c.doGet(ctx, url)
+	data, err := c.cachedGet(ctx, url, c.cacheTTL.EntityTTL, entityTag(attachmentID))
 	if err != nil {
 		return nil, err
 	}
@@ -55,13 +57,11 @@ func (c *httpClient) GetAttachmentMetadata(ctx context.Context, attachmentID int
 	return &att, nil
 }
 
-// DownloadAttachment downloads attachment content, validating the URL first
+// DownloadAttachment downloads attachment content, validating the URL first.
+// Like send, it is refused while the circuit breaker is open and reports its
+// outcome to the breaker.
 func (c *httpClient) DownloadAttachment(ctx context.Context, uri string) ([]byte, string, error) {
-	// Build full URL if relative
-	downloadURL := uri
-	if !strings.HasPrefix(uri, "http") {
-		downloadURL = c.baseURL[:strings.Index(c.baseURL, "/api/v1")] + uri
-	}
+	downloadURL := c.resolveURL(uri)
 
 	// SSRF validation
 	if err := validateURL(downloadURL, c.baseURL); err != nil {
@@ -69,26 +69,51 @@ func (c *httpClient) DownloadAttachment(ctx context.Context, uri string) ([]byte
 	}
 
 	// Use a dedicated HTTP client that re-applies auth on redirects.
-	// Go's default client drops headers/query params on redirect, which
+	// Go's default client drops query params on redirect, which
 	// causes the TP server to return a login page instead of the file.
 	downloadClient := &http.Client{
-		Timeout: c.httpClient.Timeout,
+		Timeout:   c.httpClient.Timeout,
+		Transport: c.httpClient.Transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return fmt.Errorf("too many redirects")
 			}
-			// Re-apply auth on every redirect so the access token isn't lost
+			// Credentials only ever go to the TP host; Go keeps the
+			// Authorization header on redirects to its subdomains, so drop it
+			if validateURL(req.URL.String(), c.baseURL) != nil {
+				req.Header.Del("Authorization")
+				return nil
+			}
 			c.auth.ApplyAuth(req)
 			return nil
 		},
 	}
 
+	if err := c.breaker.allow(); err != nil {
+		return nil, "", err
+	}
+	token := c.secret()
+	content, mimeType, err := c.fetchAttachment(ctx, downloadClient, downloadURL)
+	// TP rejected the token, or answered with its login page, so fetching
+	// again is worth it once the token is fresh
+	if isUnauthorized(err) && c.refreshToken(ctx, token) {
+		content, mimeType, err = c.fetchAttachment(ctx, downloadClient, downloadURL)
+	}
+	c.breaker.record(ctx, err)
+	return content, mimeType, err
+}
+
+// fetchAttachment makes one attempt at downloading downloadURL
+func (c *httpClient) fetchAttachment(ctx context.Context, downloadClient *http.Client, downloadURL string) ([]byte, string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
 	if err != nil {
 		return nil, "", err
 	}
 	c.auth.ApplyAuth(req)
 
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, "", err
+	}
 	resp, err := downloadClient.Do(req)
 	if err != nil {
 		return nil, "", err
@@ -97,7 +122,7 @@ func (c *httpClient) DownloadAttachment(ctx context.Context, uri string) ([]byte
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("download failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, "", c.apiError(resp, body, http.MethodGet, downloadURL)
 	}
 
 	content, err := io.ReadAll(resp.Body)
@@ -107,11 +132,31 @@ func (c *httpClient) DownloadAttachment(ctx context.Context, uri string) ([]byte
 
 	mimeType := resp.Header.Get("Content-Type")
 	if strings.Contains(mimeType, "text/html") {
-		preview := string(content)
+		// TP answers a download it did not authenticate with its login page
+		preview := errors.MaskToken(string(content), c.secret())
 		if len(preview) > 500 {
 			preview = preview[:500]
 		}
-		return nil, "", fmt.Errorf("download returned HTML instead of file content (likely authentication failure): %s", preview)
+		return nil, "", &errors.PermissionError{APIError: &errors.APIError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "download returned HTML instead of file content (likely authentication failure)",
+			RawBody:    preview,
+			Context:    fmt.Sprintf("GET %s", errors.MaskToken(downloadURL, c.secret())),
+		}}
 	}
 	return content, mimeType, nil
 }
+
+// resolveURL turns a URI returned by TP into an absolute URL. Relative URIs
+// are resolved against the site root, which sits under the configured base
+// path, unless they already start with that path.
+func (c *httpClient) resolveURL(uri string) string {
+	if strings.HasPrefix(uri, "http") {
+		return uri
+	}
+	site := strings.TrimSuffix(c.baseURL, "/api/v1")
+	if u, err := url.Parse(site); err == nil && u.Path != "" && strings.HasPrefix(uri, u.Path+"/") {
+		return u.Scheme + "://" + u.Host + uri
+	}
+	return site + uri
+}
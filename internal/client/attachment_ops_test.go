@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/errors"
+)
+
+func TestDownloadAttachment_ReappliesAuthOnlyOnTPHost(t *testing.T) {
+	var storageAuth string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storageAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	}))
+	defer storage.Close()
+
+	var tpAuth []string
+	tp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tpAuth = append(tpAuth, r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/Attachment.aspx":
+			http.Redirect(w, r, "/UploadedFile.aspx", http.StatusFound)
+		case "/UploadedFile.aspx":
+			http.Redirect(w, r, storage.URL+"/blob", http.StatusFound)
+		}
+	}))
+	defer tp.Close()
+
+	c := newTestClient(tp.URL)
+	c.auth = auth.NewBearerStrategy("service-token")
+
+	content, mimeType, err := c.DownloadAttachment(context.Background(), "/Attachment.aspx?AttachmentID=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "png" || mimeType != "image/png" {
+		t.Errorf("unexpected download: %q (%s)", content, mimeType)
+	}
+	if len(tpAuth) != 2 || tpAuth[0] != "Bearer service-token" || tpAuth[1] != "Bearer service-token" {
+		t.Errorf("expected bearer auth on every TP request, got %q", tpAuth)
+	}
+	if storageAuth != "" {
+		t.Errorf("expected no credentials sent to another host, got %q", storageAuth)
+	}
+}
+
+func TestDownloadAttachment_TypedErrors(t *testing.T) {
+	tp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"Status":"NotFound","Message":"Attachment 1 not found"}`))
+		case "/login":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Sign in</html>"))
+		}
+	}))
+	defer tp.Close()
+
+	c := newTestClient(tp.URL)
+
+	_, _, err := c.DownloadAttachment(context.Background(), "/missing")
+	var notFound *errors.NotFoundError
+	if !stderrors.As(err, &notFound) || notFound.Message != "Attachment 1 not found" {
+		t.Errorf("expected NotFoundError with TP's message, got %v", err)
+	}
+	if strings.Contains(err.Error(), "test-token") {
+		t.Errorf("expected the token to be masked, got %v", err)
+	}
+
+	_, _, err = c.DownloadAttachment(context.Background(), "/login")
+	var permErr *errors.PermissionError
+	if !stderrors.As(err, &permErr) || !permErr.Unauthenticated() {
+		t.Errorf("expected an unauthenticated PermissionError for a login page, got %v", err)
+	}
+}
+
+func TestDownloadAttachment_RefreshesRotatedToken(t *testing.T) {
+	var seen []string
+	tp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("access_token")
+		seen = append(seen, token)
+		switch {
+		case token == "new-token":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		case r.URL.Path == "/login":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>Sign in</html>"))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer tp.Close()
+
+	for _, uri := range []string{"/Attachment.aspx?AttachmentID=1", "/login"} {
+		t.Run(uri, func(t *testing.T) {
+			seen = nil
+			tokens := []string{"old-token", "new-token"}
+			source := func(ctx context.Context) (string, error) {
+				token := tokens[0]
+				tokens = tokens[1:]
+				return token, nil
+			}
+			strategy, err := auth.NewRotatingStrategy(context.Background(), source, config.AuthAccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := newTestClient(tp.URL)
+			c.auth = strategy
+
+			content, _, err := c.DownloadAttachment(context.Background(), uri)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(content) != "png" || len(seen) != 2 || seen[1] != "new-token" {
+				t.Errorf("expected a retry with the new token, got %q after %q", content, seen)
+			}
+		})
+	}
+}
+
+func TestDownloadAttachment_UsesCircuitBreaker(t *testing.T) {
+	calls := 0
+	tp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer tp.Close()
+
+	c := newTestClient(tp.URL)
+	c.breaker = newCircuitBreaker("example.tpondemand.com", config.BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	if _, _, err := c.DownloadAttachment(context.Background(), "/Attachment.aspx?AttachmentID=1"); err == nil {
+		t.Fatal("expected the 503 to fail the download")
+	}
+	_, _, err := c.DownloadAttachment(context.Background(), "/Attachment.aspx?AttachmentID=1")
+	var openErr *errors.CircuitOpenError
+	if !stderrors.As(err, &openErr) {
+		t.Errorf("expected the open breaker to refuse the download, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 request to reach TP, got %d", calls)
+	}
+}
@@ -1,12 +1,42 @@
 package auth
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+
+	"tp-mcp-go/internal/config"
+)
 
 // Strategy defines how authentication is applied to HTTP requests
 type Strategy interface {
 	ApplyAuth(req *http.Request)
 }
 
+// FromConfig creates the Strategy selected by cfg.Auth.Method for the
+// credentials of cfg's instance. A token from AccessTokenFile or
+// AccessTokenCommand is read here, and the returned Strategy is a Rotator.
+func FromConfig(cfg *config.Config) (Strategy, error) {
+	if cfg.Auth.Method == config.AuthBasic {
+		return NewBasicStrategy(cfg.Auth.Username, cfg.Auth.Password), nil
+	}
+	switch {
+	case cfg.AccessTokenFile != "":
+		return NewRotatingStrategy(context.Background(), FileTokenSource(cfg.AccessTokenFile), cfg.Auth.Method)
+	case cfg.AccessTokenCommand != "":
+		return NewRotatingStrategy(context.Background(), CommandTokenSource(cfg.AccessTokenCommand), cfg.Auth.Method)
+	}
+	return ForToken(cfg.Auth.Method, cfg.AccessToken), nil
+}
+
+// ForToken creates the Strategy that sends token as method requires:
+// in a bearer header for config.AuthBearer, as a query parameter otherwise
+func ForToken(method, token string) Strategy {
+	if method == config.AuthBearer {
+		return NewBearerStrategy(token)
+	}
+	return NewAccessTokenStrategy(token)
+}
+
 type accessTokenStrategy struct {
 	token string
 }
@@ -21,3 +51,32 @@ func (s *accessTokenStrategy) ApplyAuth(req *http.Request) {
 	q.Set("access_token", s.token)
 	req.URL.RawQuery = q.Encode()
 }
+
+type bearerStrategy struct {
+	token string
+}
+
+// NewBearerStrategy creates a Strategy that sends the token in an
+// Authorization: Bearer header, keeping it out of URLs
+func NewBearerStrategy(token string) Strategy {
+	return &bearerStrategy{token: token}
+}
+
+func (s *bearerStrategy) ApplyAuth(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+s.token)
+}
+
+type basicStrategy struct {
+	username string
+	password string
+}
+
+// NewBasicStrategy creates a Strategy that uses HTTP Basic auth, as accepted
+// by on-premise Target Process installs
+func NewBasicStrategy(username, password string) Strategy {
+	return &basicStrategy{username: username, password: password}
+}
+
+func (s *basicStrategy) ApplyAuth(req *http.Request) {
+	req.SetBasicAuth(s.username, s.password)
+}
@@ -3,6 +3,8 @@ package auth
 import (
 	"net/http"
 	"testing"
+
+	"tp-mcp-go/internal/config"
 )
 
 func TestApplyAuth_AddsAccessToken(t *testing.T) {
@@ -58,3 +60,71 @@ func TestApplyAuth_EmptyExistingQuery(t *testing.T) {
 		t.Errorf("expected access_token=empty-test-token, got %s", query.Get("access_token"))
 	}
 }
+
+func TestBearerStrategy_SetsHeader(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://api.example.com/endpoint?format=json", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	NewBearerStrategy("service-token").ApplyAuth(req)
+
+	if got := req.Header.Get("Authorization"); got != "Bearer service-token" {
+		t.Errorf("expected Authorization: Bearer service-token, got %q", got)
+	}
+	if req.URL.Query().Has("access_token") {
+		t.Errorf("expected no access_token parameter, got %s", req.URL.RawQuery)
+	}
+}
+
+func TestBasicStrategy_SetsCredentials(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://tp.example.com/api/v1/UserStories", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	NewBasicStrategy("admin", "s3cret").ApplyAuth(req)
+
+	user, pass, ok := req.BasicAuth()
+	if !ok || user != "admin" || pass != "s3cret" {
+		t.Errorf("expected basic auth admin/s3cret, got %q/%q (ok=%t)", user, pass, ok)
+	}
+	if req.URL.RawQuery != "" {
+		t.Errorf("expected no query parameters, got %s", req.URL.RawQuery)
+	}
+}
+
+func TestFromConfig_SelectsStrategy(t *testing.T) {
+	tests := []struct {
+		method string
+		check  func(*http.Request) bool
+	}{
+		{config.AuthAccessToken, func(r *http.Request) bool { return r.URL.Query().Get("access_token") == "tok" }},
+		{config.AuthBearer, func(r *http.Request) bool { return r.Header.Get("Authorization") == "Bearer tok" }},
+		{config.AuthBasic, func(r *http.Request) bool {
+			user, pass, ok := r.BasicAuth()
+			return ok && user == "u" && pass == "p"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			cfg := &config.Config{
+				AccessToken: "tok",
+				Auth:        config.AuthConfig{Method: tt.method, Username: "u", Password: "p"},
+			}
+			req, err := http.NewRequest("GET", "https://api.example.com/endpoint", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			strategy, err := FromConfig(cfg)
+			if err != nil {
+				t.Fatalf("FromConfig: unexpected error: %v", err)
+			}
+			strategy.ApplyAuth(req)
+			if !tt.check(req) {
+				t.Errorf("unexpected auth for %s: header %q, query %q", tt.method, req.Header.Get("Authorization"), req.URL.RawQuery)
+			}
+		})
+	}
+}
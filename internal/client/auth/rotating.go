@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sourceTimeout bounds a single read of a token file or run of a token command
+const sourceTimeout = 30 * time.Second
+
+// Rotator is a Strategy whose token can change while the server runs
+type Rotator interface {
+	Strategy
+	// Token returns the token currently applied to requests
+	Token() string
+	// Refresh re-reads the token unless it already differs from stale, as it
+	// does when another caller refreshed it first, and reports whether the
+	// token now differs from stale
+	Refresh(ctx context.Context, stale string) (bool, error)
+}
+
+// TokenSource reads the current access token
+type TokenSource func(ctx context.Context) (string, error)
+
+// FileTokenSource reads the token from path, as written by a secrets agent
+func FileTokenSource(path string) TokenSource {
+	return func(ctx context.Context) (string, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read access token file: %w", err)
+		}
+		return nonEmptyToken(string(b), "access token file "+path)
+	}
+}
+
+// CommandTokenSource runs command through the system shell and reads the
+// token from its standard output
+func CommandTokenSource(command string) TokenSource {
+	return func(ctx context.Context) (string, error) {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("run access token command: %w: %s", err, msg)
+			}
+			return "", fmt.Errorf("run access token command: %w", err)
+		}
+		return nonEmptyToken(string(out), "access token command")
+	}
+}
+
+// nonEmptyToken trims surrounding whitespace from a token read from source
+func nonEmptyToken(s, source string) (string, error) {
+	if token := strings.TrimSpace(s); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("%s returned an empty token", source)
+}
+
+// rotatingStrategy applies the token most recently read from a TokenSource
+type rotatingStrategy struct {
+	source  TokenSource
+	method  string
+	current atomic.Pointer[appliedToken]
+	// mu serializes reads of the source
+	mu sync.Mutex
+}
+
+// appliedToken keeps a token and the strategy applying it together, so a
+// rotation swaps both at once
+type appliedToken struct {
+	token    string
+	strategy Strategy
+}
+
+// NewRotatingStrategy reads the initial token from source and returns a
+// Rotator that applies it with method, as ForToken does
+func NewRotatingStrategy(ctx context.Context, source TokenSource, method string) (Rotator, error) {
+	s := &rotatingStrategy{source: source, method: method}
+	if _, err := s.Refresh(ctx, ""); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *rotatingStrategy) ApplyAuth(req *http.Request) {
+	s.current.Load().strategy.ApplyAuth(req)
+}
+
+func (s *rotatingStrategy) Token() string {
+	if t := s.current.Load(); t != nil {
+		return t.token
+	}
+	return ""
+}
+
+func (s *rotatingStrategy) Refresh(ctx context.Context, stale string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current := s.Token(); current != stale {
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+	token, err := s.source(ctx)
+	if err != nil {
+		return false, err
+	}
+	s.current.Store(&appliedToken{token: token, strategy: ForToken(s.method, token)})
+	return token != stale, nil
+}
+
+// RefreshEvery re-reads r's token every interval until ctx is done, reporting
+// failures to onError; the previous token stays in use when a read fails
+func RefreshEvery(ctx context.Context, r Rotator, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Refresh(ctx, r.Token()); err != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"tp-mcp-go/internal/config"
+)
+
+func TestRotatingStrategy_FileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRotatingStrategy(context.Background(), FileTokenSource(path), config.AuthBearer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Token() != "first-token" {
+		t.Fatalf("Token() = %q, want first-token", r.Token())
+	}
+
+	if err := os.WriteFile(path, []byte("second-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := r.Refresh(context.Background(), "first-token")
+	if err != nil || !changed {
+		t.Fatalf("Refresh() = %t, %v; want true, nil", changed, err)
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.example.com/endpoint", nil)
+	r.ApplyAuth(req)
+	if got := req.Header.Get("Authorization"); got != "Bearer second-token" {
+		t.Errorf("expected the rotated token to be applied, got %q", got)
+	}
+}
+
+func TestRotatingStrategy_SkipsReadWhenAlreadyRotated(t *testing.T) {
+	reads := 0
+	source := func(ctx context.Context) (string, error) {
+		reads++
+		return fmt.Sprintf("token-%d", reads), nil
+	}
+
+	r, err := NewRotatingStrategy(context.Background(), source, config.AuthAccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A caller still holding an older token finds it already replaced
+	changed, err := r.Refresh(context.Background(), "token-0")
+	if err != nil || !changed {
+		t.Fatalf("Refresh() = %t, %v; want true, nil", changed, err)
+	}
+	if reads != 1 {
+		t.Errorf("expected no extra read of the source, got %d reads", reads)
+	}
+}
+
+func TestRotatingStrategy_KeepsTokenOnFailedRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("kept-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRotatingStrategy(context.Background(), FileTokenSource(path), config.AuthAccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Refresh(context.Background(), r.Token()); err == nil {
+		t.Error("expected an error for an empty token file")
+	}
+	if r.Token() != "kept-token" {
+		t.Errorf("Token() = %q, want the previous token kept", r.Token())
+	}
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	token, err := CommandTokenSource("echo command-token")(context.Background())
+	if err != nil || token != "command-token" {
+		t.Errorf("got %q, %v; want command-token", token, err)
+	}
+
+	if _, err := CommandTokenSource("echo denied >&2; exit 3")(context.Background()); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+}
+
+func TestFromConfig_TokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	strategy, err := FromConfig(&config.Config{AccessTokenFile: path, Auth: config.AuthConfig{Method: config.AuthAccessToken}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := strategy.(Rotator)
+	if !ok || r.Token() != "file-token" {
+		t.Fatalf("expected a Rotator holding file-token, got %T", strategy)
+	}
+
+	if _, err := FromConfig(&config.Config{AccessTokenFile: filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Error("expected an error for a missing token file")
+	}
+}
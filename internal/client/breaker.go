@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/errors"
+)
+
+// Circuit breaker states
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half-open"
+)
+
+// circuitBreaker fails requests fast once an instance looks down. It opens
+// after threshold consecutive failures, and after openTimeout lets a single
+// probe request through: success closes it again, failure re-opens it. A nil
+// circuitBreaker lets everything through.
+type circuitBreaker struct {
+	mu          sync.Mutex
+	domain      string
+	threshold   int
+	openTimeout time.Duration
+
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	now func() time.Time
+}
+
+// BreakerStatus is a snapshot of a circuit breaker for diagnostics
+type BreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	FailureThreshold    int        `json:"failureThreshold"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	// RetryIn is the time until an open breaker lets a probe request through
+	RetryIn string `json:"retryIn,omitempty"`
+}
+
+// breakers holds one circuitBreaker per domain and settings, so every client
+// for an instance, including per-session clients, sees the same state
+var breakers = struct {
+	sync.Mutex
+	m map[string]*circuitBreaker
+}{m: make(map[string]*circuitBreaker)}
+
+// sharedBreaker returns the breaker for domain under cfg, or nil when cfg disables it
+func sharedBreaker(domain string, cfg config.BreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		return nil
+	}
+	key := fmt.Sprintf("%s|%d|%s", domain, cfg.FailureThreshold, cfg.OpenTimeout)
+	breakers.Lock()
+	defer breakers.Unlock()
+	b, ok := breakers.m[key]
+	if !ok {
+		b = newCircuitBreaker(domain, cfg)
+		breakers.m[key] = b
+	}
+	return b
+}
+
+func newCircuitBreaker(domain string, cfg config.BreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		domain:      domain,
+		threshold:   cfg.FailureThreshold,
+		openTimeout: cfg.OpenTimeout,
+		state:       breakerClosed,
+		now:         time.Now,
+	}
+}
+
+// allow returns a *errors.CircuitOpenError when a request must not be sent.
+// Every allowed request must be followed by a call to record.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if wait := b.openTimeout - b.now().Sub(b.openedAt); wait > 0 {
+			return &errors.CircuitOpenError{Domain: b.domain, RetryIn: wait}
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+	case breakerHalfOpen:
+		if b.probing {
+			return &errors.CircuitOpenError{Domain: b.domain}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request made
+// with ctx
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// The caller gave up, which says nothing about TP; a half-open
+		// breaker lets the next request probe
+	case !isOutage(err):
+		b.state = breakerClosed
+		b.failures = 0
+	default:
+		b.failures++
+		if b.state == breakerHalfOpen || b.failures >= b.threshold {
+			b.state = breakerOpen
+			b.openedAt = b.now()
+		}
+	}
+}
+
+// isOutage reports whether err suggests TP is unavailable: a transport
+// failure or a 5xx response. Other responses show TP is up.
+func isOutage(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// status returns a snapshot of the breaker, or nil when there is none
+func (b *circuitBreaker) status() *BreakerStatus {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := &BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		FailureThreshold:    b.threshold,
+	}
+	if b.state != breakerClosed {
+		openedAt := b.openedAt
+		s.OpenedAt = &openedAt
+	}
+	if b.state == breakerOpen {
+		if wait := b.openTimeout - b.now().Sub(b.openedAt); wait > 0 {
+			s.RetryIn = wait.Round(time.Second).String()
+		}
+	}
+	return s
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/errors"
+)
+
+// newTestBreaker returns a breaker whose clock is controlled by the returned pointer
+func newTestBreaker(threshold int) (*circuitBreaker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker("example.tpondemand.com", config.BreakerConfig{FailureThreshold: threshold, OpenTimeout: 30 * time.Second})
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail(t *testing.T, b *circuitBreaker, err error) {
+	t.Helper()
+	if allowErr := b.allow(); allowErr != nil {
+		t.Fatalf("allow: unexpected error: %v", allowErr)
+	}
+	b.record(context.Background(), err)
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3)
+	serverErr := &errors.APIError{StatusCode: 503, Message: "unavailable"}
+
+	fail(t, b, serverErr)
+	fail(t, b, serverErr)
+	if s := b.status(); s.State != breakerClosed || s.ConsecutiveFailures != 2 {
+		t.Fatalf("status = %+v, want closed with 2 failures", s)
+	}
+	fail(t, b, serverErr)
+
+	err := b.allow()
+	var openErr *errors.CircuitOpenError
+	if !stderrors.As(err, &openErr) {
+		t.Fatalf("expected CircuitOpenError, got %v", err)
+	}
+	if openErr.RetryIn != 30*time.Second {
+		t.Errorf("RetryIn = %s, want 30s", openErr.RetryIn)
+	}
+	if errors.IsRetryable(err) {
+		t.Error("CircuitOpenError must not be retried")
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2)
+
+	fail(t, b, stderrors.New("connection refused"))
+	fail(t, b, nil)
+	fail(t, b, stderrors.New("connection refused"))
+
+	if s := b.status(); s.State != breakerClosed || s.ConsecutiveFailures != 1 {
+		t.Errorf("status = %+v, want closed with 1 failure", s)
+	}
+}
+
+func TestCircuitBreaker_ClientErrorsDoNotCount(t *testing.T) {
+	b, _ := newTestBreaker(1)
+
+	fail(t, b, &errors.APIError{StatusCode: 404, Message: "not found"})
+	// A request the caller cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.allow(); err != nil {
+		t.Fatalf("allow: unexpected error: %v", err)
+	}
+	b.record(ctx, ctx.Err())
+
+	if err := b.allow(); err != nil {
+		t.Errorf("expected breaker to stay closed, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	b, now := newTestBreaker(1)
+	fail(t, b, &errors.APIError{StatusCode: 500, Message: "boom"})
+
+	*now = now.Add(31 * time.Second)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected a probe to be allowed, got %v", err)
+	}
+	if s := b.status(); s.State != breakerHalfOpen {
+		t.Errorf("state = %s, want %s", s.State, breakerHalfOpen)
+	}
+	// Only one probe at a time
+	if err := b.allow(); err == nil {
+		t.Error("expected a second request to fail fast while the probe runs")
+	}
+
+	b.record(context.Background(), nil)
+	if s := b.status(); s.State != breakerClosed || s.ConsecutiveFailures != 0 {
+		t.Errorf("status = %+v, want closed after a successful probe", s)
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	b, now := newTestBreaker(2)
+	fail(t, b, stderrors.New("timeout"))
+	fail(t, b, stderrors.New("timeout"))
+
+	*now = now.Add(31 * time.Second)
+	fail(t, b, stderrors.New("timeout"))
+
+	s := b.status()
+	if s.State != breakerOpen {
+		t.Fatalf("state = %s, want %s", s.State, breakerOpen)
+	}
+	if s.RetryIn != "30s" {
+		t.Errorf("RetryIn = %q, want 30s", s.RetryIn)
+	}
+}
+
+func TestCircuitBreaker_FailsRequestsFast(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.domain = "example.tpondemand.com"
+	c.breaker = newCircuitBreaker(c.domain, config.BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+
+	if _, err := c.doRequest(context.Background(), "GET", c.baseURL+"/UserStories", nil); err == nil {
+		t.Fatal("expected a 503 error")
+	}
+	_, err := c.doRequest(context.Background(), "GET", c.baseURL+"/UserStories", nil)
+	var openErr *errors.CircuitOpenError
+	if !stderrors.As(err, &openErr) {
+		t.Fatalf("expected CircuitOpenError, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 request to reach the server, got %d", calls)
+	}
+
+	diagnostics := c.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].CircuitBreaker == nil || diagnostics[0].CircuitBreaker.State != breakerOpen {
+		t.Errorf("unexpected diagnostics: %+v", diagnostics)
+	}
+}
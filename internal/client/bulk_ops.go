@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// BulkResult is the outcome of one item of a bulk request
+type BulkResult struct {
+	// Index is the item's position in the request
+	Index   int            `json:"index"`
+	Success bool           `json:"success"`
+	Entity  map[string]any `json:"entity,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// BulkCreate creates every item in one request to the bulk endpoint. The
+// error is non-nil only when the request as a whole failed; items TP
+// rejected are reported in their BulkResult.
+func (c *httpClient) BulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]BulkResult, error) {
+	req, err := c.PreviewBulkCreate(ctx, entityType, items)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.doCreate(ctx, req.URL, req.Items, unverifiableCreate)
+	c.invalidateEntities(touchedItems(items)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseBulkResponse(data, len(items))
+}
+
+// BulkUpdate updates every item, each carrying its Id, in one request to the
+// bulk endpoint. Errors are reported as for BulkCreate.
+func (c *httpClient) BulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]BulkResult, error) {
+	req, err := c.PreviewBulkUpdate(ctx, entityType, items)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.doPost(ctx, req.URL, req.Items)
+	c.invalidateEntities(touchedItems(items)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseBulkResponse(data, len(items))
+}
+
+// touchedItems returns the entities touched by the items of a bulk write
+func touchedItems(items []map[string]any) []int {
+	var ids []int
+	for _, item := range items {
+		ids = append(ids, touchedEntities(item)...)
+	}
+	return ids
+}
+
+// unverifiableCreate is the lookup of a bulk create: which of its items an
+// earlier attempt created cannot be told reliably, so it is never re-sent
+// after a failure TP may have processed
+func unverifiableCreate(ctx context.Context, since time.Time) ([]byte, error) {
+	return nil, fmt.Errorf("a bulk create cannot be matched to the entities it may have created")
+}
+
+// parseBulkResponse reads the per-item results of a bulk request of n items.
+// TP answers with the items in request order, either as an array or in an
+// Items envelope; an item without an Id is a failure.
+func parseBulkResponse(data []byte, n int) ([]BulkResult, error) {
+	var items []map[string]any
+	if err := json.Unmarshal(data, &items); err != nil {
+		var envelope struct {
+			Items []map[string]any `json:"Items"`
+		}
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, fmt.Errorf("unexpected bulk response: %w", err)
+		}
+		items = envelope.Items
+	}
+
+	results := make([]BulkResult, n)
+	for i := range results {
+		results[i].Index = i
+		if i >= len(items) {
+			results[i].Error = "no result returned for this item"
+			continue
+		}
+		if msg := bulkItemError(items[i]); msg != "" {
+			results[i].Error = msg
+			continue
+		}
+		results[i].Success = true
+		results[i].Entity = items[i]
+	}
+	return results, nil
+}
+
+// bulkItemError returns the failure TP reported for a bulk item, if any
+func bulkItemError(item map[string]any) string {
+	switch e := item["Error"].(type) {
+	case string:
+		return e
+	case map[string]any:
+		if msg, ok := e["Message"].(string); ok {
+			return msg
+		}
+		return fmt.Sprint(e)
+	}
+	if status, _ := item["Status"].(string); status == "Error" {
+		if msg, ok := item["Message"].(string); ok {
+			return msg
+		}
+		return "item failed"
+	}
+	if _, ok := item["Id"]; !ok {
+		return "item failed without an error message"
+	}
+	return ""
+}
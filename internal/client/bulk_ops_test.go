@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+func TestBulkCreate_ReportsEachItem(t *testing.T) {
+	var captured []map[string]any
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&captured)
+		w.Write([]byte(`{"Items":[{"Id":11,"Name":"First"},{"Error":{"Message":"Name is too long"}}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	items := []map[string]any{{"Name": "First"}, {"Name": "Second"}, {"Name": "Third"}}
+	results, err := c.BulkCreate(context.Background(), entity.TypeTask, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if path != "/api/v1/Tasks/bulk" {
+		t.Errorf("path = %q, want /api/v1/Tasks/bulk", path)
+	}
+	if len(captured) != 3 || captured[1]["Name"] != "Second" {
+		t.Errorf("unexpected request body: %v", captured)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if !results[0].Success || results[0].Entity["Id"] != float64(11) {
+		t.Errorf("expected item 0 to succeed, got %+v", results[0])
+	}
+	if results[1].Success || results[1].Error != "Name is too long" {
+		t.Errorf("expected item 1 to fail with TP's message, got %+v", results[1])
+	}
+	if results[2].Success || results[2].Index != 2 {
+		t.Errorf("expected item 2 without a result to fail, got %+v", results[2])
+	}
+}
+
+func TestBulkCreate_NotResentAfterServerError(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+	if _, err := c.BulkCreate(context.Background(), entity.TypeTask, []map[string]any{{"Name": "First"}}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected the bulk create to be sent once, got %d requests", calls.Load())
+	}
+}
+
+func TestBulkUpdate_ArrayResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"Id":1,"Name":"Renamed"},{"Status":"Error","Message":"Entity 2 not found"}]`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	results, err := c.BulkUpdate(context.Background(), entity.TypeBug, []map[string]any{
+		{"Id": 1, "Name": "Renamed"},
+		{"Id": 2, "Name": "Missing"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].Success || results[1].Success || results[1].Error != "Entity 2 not found" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
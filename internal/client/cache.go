@@ -0,0 +1,187 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type bypassCacheKey struct{}
+
+// WithoutCache returns a context whose GET requests go to Target Process even
+// when a cached response exists; the fresh response replaces the cached one
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// cacheBypassed reports whether ctx was created by WithoutCache
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// responseCache is an LRU cache of GET response bodies keyed by URL. Each
+// entry is tagged with the entities it describes so a write can evict every
+// response that shows the entities it touched.
+type responseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front is most recently used
+	entries    map[string]*list.Element
+	// generation counts invalidations, so a response fetched while a write
+	// was invalidating is not stored
+	generation uint64
+	now        func() time.Time
+}
+
+type cacheEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+	tags    []string
+}
+
+func newResponseCache(maxEntries int) *responseCache {
+	return &responseCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// entityTag is the tag of responses describing the entity with the given ID.
+// TP entity IDs are unique across types, so the type is left out: a comment
+// can evict its entity without knowing what kind of entity it is.
+func entityTag(id int) string {
+	return fmt.Sprintf("entity:%d", id)
+}
+
+// get returns the unexpired response cached for key, along with the current
+// generation to pass to put after a miss
+func (c *responseCache) get(key string) ([]byte, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil, c.generation, false
+	}
+	c.order.MoveToFront(el)
+	return e.data, c.generation, true
+}
+
+// put caches data for key unless an invalidation happened since generation
+// was read, evicting the least recently used entries beyond maxEntries
+func (c *responseCache) put(key string, data []byte, ttl time.Duration, generation uint64, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, data: data, expires: c.now().Add(ttl), tags: tags})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// invalidate evicts every entry carrying one of tags
+func (c *responseCache) invalidate(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		if hasAnyTag(el.Value.(*cacheEntry).tags, tags) {
+			c.remove(el)
+		}
+		el = next
+	}
+}
+
+func (c *responseCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+func hasAnyTag(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cachedGet performs a GET request through the response cache, caching the
+// response for ttl under tags. Without a cache, or with a zero ttl, it is
+// doGet.
+func (c *httpClient) cachedGet(ctx context.Context, url string, ttl time.Duration, tags ...string) ([]byte, error) {
+	if c.cache == nil || ttl <= 0 {
+		return c.doGet(ctx, url)
+	}
+	data, generation, ok := c.cache.get(url)
+	if ok && !cacheBypassed(ctx) {
+		return data, nil
+	}
+	data, err := c.doGet(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.put(url, data, ttl, generation, tags...)
+	return data, nil
+}
+
+// invalidateEntities evicts the cached responses describing the entities
+// with the given IDs. Writes call it whether or not they succeeded, since a
+// failed write may still have been applied.
+func (c *httpClient) invalidateEntities(ids ...int) {
+	if c.cache == nil || len(ids) == 0 {
+		return
+	}
+	tags := make([]string, len(ids))
+	for i, id := range ids {
+		tags[i] = entityTag(id)
+	}
+	c.cache.invalidate(tags...)
+}
+
+// touchedEntities returns the ID of an entity being written, if it has one,
+// and of every entity its fields reference, such as {"UserStory": {"Id": 5}}:
+// a parent's cached response may list the children being written
+func touchedEntities(data map[string]any) []int {
+	var ids []int
+	if id, ok := intField(data["Id"]); ok {
+		ids = append(ids, id)
+	}
+	for _, v := range data {
+		if ref, ok := v.(map[string]any); ok {
+			if id, ok := intField(ref["Id"]); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// intField reads a JSON number, as decoded or as set by Go code
+func intField(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// newCachingTestClient returns a test client whose GET responses are cached
+func newCachingTestClient(serverURL string, maxEntries int) *httpClient {
+	c := newTestClient(serverURL)
+	c.cache = newResponseCache(maxEntries)
+	c.cacheTTL = config.CacheConfig{Enabled: true, MaxEntries: maxEntries, EntityTTL: time.Minute, ListTTL: time.Minute, MetadataTTL: time.Minute}
+	return c
+}
+
+// countingServer answers every request with an entity and counts the GETs
+func countingServer(t *testing.T, gets *atomic.Int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+		}
+		w.Write([]byte(`{"Id":1,"Name":"Story"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCache_ServesRepeatedGets(t *testing.T) {
+	var gets atomic.Int32
+	c := newCachingTestClient(countingServer(t, &gets).URL, 10)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetEntity(ctx, entity.TypeUserStory, 1, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if gets.Load() != 1 {
+		t.Errorf("expected 1 GET, got %d", gets.Load())
+	}
+
+	if _, err := c.GetEntity(WithoutCache(ctx), entity.TypeUserStory, 1, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gets.Load() != 2 {
+		t.Errorf("expected WithoutCache to reach TP, got %d GETs", gets.Load())
+	}
+}
+
+func TestCache_WritesInvalidateTouchedEntities(t *testing.T) {
+	var gets atomic.Int32
+	c := newCachingTestClient(countingServer(t, &gets).URL, 10)
+	ctx := context.Background()
+
+	c.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	c.GetEntity(ctx, entity.TypeUserStory, 1, []string{"Tasks"})
+	c.GetEntity(ctx, entity.TypeBug, 2, nil)
+	c.ListComments(ctx, 3, 25, nil)
+
+	c.UpdateEntity(ctx, entity.TypeUserStory, 1, map[string]any{"Name": "Renamed"})
+	c.CreateComment(ctx, 3, "Looks good")
+	before := gets.Load()
+
+	c.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	c.GetEntity(ctx, entity.TypeUserStory, 1, []string{"Tasks"})
+	c.ListComments(ctx, 3, 25, nil)
+	if got := gets.Load() - before; got != 3 {
+		t.Errorf("expected the updated and commented entities to be refetched, got %d GETs", got)
+	}
+
+	// Creating a task under story 2 evicts the story, which may list its tasks
+	c.CreateEntity(ctx, entity.TypeTask, map[string]any{"Name": "Task", "UserStory": map[string]any{"Id": float64(2)}})
+	before = gets.Load()
+	c.GetEntity(ctx, entity.TypeBug, 2, nil)
+	if gets.Load() == before {
+		t.Error("expected the referenced entity to be refetched after a create")
+	}
+}
+
+func TestCache_TeamAssignmentUpdateInvalidatesItsEntity(t *testing.T) {
+	var gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+			w.Write([]byte(`{"Id":1,"Name":"Story"}`))
+			return
+		}
+		w.Write([]byte(`{"Id":40,"Assignable":{"Id":1},"EntityState":{"Id":21}}`))
+	}))
+	t.Cleanup(server.Close)
+	c := newCachingTestClient(server.URL, 10)
+	ctx := context.Background()
+
+	c.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	if _, err := c.UpdateEntity(ctx, "TeamAssignment", 40, map[string]any{"EntityState": map[string]any{"Id": 21}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	if gets.Load() != 2 {
+		t.Errorf("expected the assigned entity to be refetched, got %d GETs", gets.Load())
+	}
+}
+
+func TestCache_TTLAndDisabledResources(t *testing.T) {
+	var gets atomic.Int32
+	c := newCachingTestClient(countingServer(t, &gets).URL, 10)
+	now := time.Now()
+	c.cache.now = func() time.Time { return now }
+	c.cacheTTL.ListTTL = 0
+	ctx := context.Background()
+
+	c.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	now = now.Add(2 * time.Minute)
+	c.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	if gets.Load() != 2 {
+		t.Errorf("expected an expired entry to be refetched, got %d GETs", gets.Load())
+	}
+
+	c.ListAttachments(ctx, 1, 10)
+	c.ListAttachments(ctx, 1, 10)
+	if gets.Load() != 4 {
+		t.Errorf("expected a zero TTL to disable caching, got %d GETs", gets.Load())
+	}
+}
+
+func TestResponseCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResponseCache(2)
+	_, gen, _ := cache.get("a")
+	cache.put("a", []byte("a"), time.Minute, gen)
+	cache.put("b", []byte("b"), time.Minute, gen)
+	cache.get("a")
+	cache.put("c", []byte("c"), time.Minute, gen)
+
+	if _, _, ok := cache.get("b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, _, ok := cache.get("a"); !ok {
+		t.Error("expected the recently used entry to be kept")
+	}
+}
+
+func TestResponseCache_SkipsPutAfterInvalidation(t *testing.T) {
+	cache := newResponseCache(10)
+	_, gen, _ := cache.get("a")
+	// A write lands while the GET for "a" is in flight
+	cache.invalidate(entityTag(1))
+	cache.put("a", []byte("stale"), time.Minute, gen, entityTag(1))
+
+	if _, _, ok := cache.get("a"); ok {
+		t.Error("expected a response fetched before an invalidation not to be cached")
+	}
+}
@@ -9,11 +9,26 @@ import (
 type Client interface {
 	// Search
 	SearchEntities(ctx context.Context, req query.SearchRequest) (*query.PaginatedResponse, error)
+	// QueryV2 uses the v2 API for shaped results: projections, nested collections and aggregates
+	QueryV2(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error)
 
 	// Entity CRUD
 	GetEntity(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error)
 	CreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error)
 	UpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error)
+	DeleteEntity(ctx context.Context, entityType entity.Type, id int) error
+
+	// Bulk writes — one request for many items, with a result per item
+	BulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]BulkResult, error)
+	BulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]BulkResult, error)
+
+	// Write previews — the request each write would send, for dry runs
+	PreviewCreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (*WriteRequest, error)
+	PreviewUpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (*WriteRequest, error)
+	PreviewDeleteEntity(ctx context.Context, entityType entity.Type, id int) (*WriteRequest, error)
+	PreviewBulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) (*WriteRequest, error)
+	PreviewBulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) (*WriteRequest, error)
+	PreviewCreateComment(ctx context.Context, entityID int, description string) (*WriteRequest, error)
 
 	// Comments — note: ListComments has an include parameter
 	CreateComment(ctx context.Context, entityID int, description string) (*entity.Comment, error)
@@ -27,5 +42,7 @@ type Client interface {
 	// Metadata
 	FetchMetadata(ctx context.Context) (any, error)
 	GetValidEntityTypes(ctx context.Context) ([]string, error)
+	GetCustomFields(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error)
+	GetEntityStates(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error)
 	InitializeCache(ctx context.Context) error
 }
@@ -11,13 +11,12 @@ import (
 
 // CreateComment creates a private comment on an entity
 func (c *httpClient) CreateComment(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
-	url := fmt.Sprintf("%s/Comments", c.baseURL)
-	body := map[string]any{
-		"Description": description,
-		"General":     map[string]any{"Id": entityID},
-		"IsPrivate":   true,
+	req, err := c.PreviewCreateComment(ctx, entityID, description)
+	if err != nil {
+		return nil, err
 	}
-	data, err := c.doPost(ctx, url, body)
+	data, err := c.doCreate(ctx, req.URL, req.Body, c.lookupCreatedComment(entityID, description))
+	c.invalidateEntities(entityID)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +36,7 @@ func (c *httpClient) ListComments(ctx context.Context, entityID int, take int, i
 	url := fmt.Sprintf("%s/Comments?where=General.Id eq %d&take=%d&include=[%s]&orderByDesc=CreateDate",
 		c.baseURL, entityID, take, strings.Join(include, ","))
 
-	data, err := c.doGet(ctx, url)
+	data, err := c.cachedGet(ctx, url, c.cacheTTL.ListTTL, entityTag(entityID))
 	if err != nil {
 		return nil, err
 	}
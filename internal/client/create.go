@@ -0,0 +1,193 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/errors"
+	"tp-mcp-go/internal/domain/query"
+)
+
+// createClockSkew widens the window in which a lookup accepts an entity as
+// the one an earlier attempt created, which starts when the first attempt
+// was sent, to allow for clock drift between this server and TP
+const createClockSkew = 10 * time.Second
+
+// createLookupTake is how many candidates a lookup reads; seeing more than
+// one match is enough to know the match is ambiguous
+const createLookupTake = 10
+
+// createLookup finds what an earlier attempt at a create produced, returning
+// nil when nothing created since the given time matches
+type createLookup func(ctx context.Context, since time.Time) ([]byte, error)
+
+// doCreate POSTs a create request. A create is not idempotent, so after a
+// failure that TP may have processed anyway (a timeout, a dropped connection
+// or a 5xx) lookup checks whether the entity exists before trying again. If
+// the check fails, or cannot tell which entity the create produced, the
+// create is not repeated.
+func (c *httpClient) doCreate(ctx context.Context, url string, body any, lookup createLookup) ([]byte, error) {
+	var since time.Time
+	var lastErr error
+	return executeWithRetry(ctx, func() ([]byte, error) {
+		if since.IsZero() {
+			since = time.Now().Add(-createClockSkew)
+		}
+		if lastErr != nil && mayHaveLanded(lastErr) {
+			found, err := lookup(ctx, since)
+			if err != nil {
+				return nil, &finalError{fmt.Errorf("create failed (%v) and checking whether it was applied failed: %w; verify before creating again", lastErr, err)}
+			}
+			if found != nil {
+				return found, nil
+			}
+		}
+		data, err := c.send(ctx, http.MethodPost, url, body)
+		lastErr = err
+		return data, err
+	}, c.retryConfig)
+}
+
+// mayHaveLanded reports whether TP may have applied a request that failed
+// with err. 4xx responses, including 429, mean the request was refused.
+func mayHaveLanded(err error) bool {
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// lookupCreatedEntity finds the entity of entityType an earlier attempt at
+// creating data produced: one with the same Name, the same single-valued
+// references, such as {"Project": {"Id": 5}}, and the current user as Owner
+func (c *httpClient) lookupCreatedEntity(entityType entity.Type, data map[string]any) createLookup {
+	return func(ctx context.Context, since time.Time) ([]byte, error) {
+		name, ok := data["Name"].(string)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("cannot identify an entity without a Name")
+		}
+		owner, err := c.currentUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot identify the current user: %w", err)
+		}
+		refs := map[string]int{"Owner": owner}
+		for field, v := range data {
+			// AssignedUser is a collection, which cannot be compared by Id
+			if ref, ok := v.(map[string]any); ok && field != "AssignedUser" {
+				if id, ok := intField(ref["Id"]); ok {
+					refs[field] = id
+				}
+			}
+		}
+
+		conditions := []string{query.FormatStringCondition("Name", "eq", name)}
+		for _, field := range slices.Sorted(maps.Keys(refs)) {
+			conditions = append(conditions, query.FormatNumberCondition(field+".Id", "eq", refs[field]))
+		}
+		params := url.Values{}
+		params.Set("where", strings.Join(conditions, " and "))
+		params.Set("orderByDesc", "Id")
+		params.Set("take", strconv.Itoa(createLookupTake))
+		return c.findCreated(ctx, c.buildURL(entityType)+"?"+params.Encode(), since, func(item map[string]any) bool {
+			if item["Name"] != name {
+				return false
+			}
+			// References the response carries must agree with the request
+			for field, id := range refs {
+				if ref, ok := item[field].(map[string]any); ok {
+					if got, ok := intField(ref["Id"]); ok && got != id {
+						return false
+					}
+				}
+			}
+			return true
+		})
+	}
+}
+
+// lookupCreatedComment finds a comment on entityID with the given description
+func (c *httpClient) lookupCreatedComment(entityID int, description string) createLookup {
+	return func(ctx context.Context, since time.Time) ([]byte, error) {
+		owner, err := c.currentUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot identify the current user: %w", err)
+		}
+		params := url.Values{}
+		params.Set("where", query.FormatNumberCondition("General.Id", "eq", entityID)+" and "+
+			query.FormatNumberCondition("Owner.Id", "eq", owner))
+		params.Set("orderByDesc", "CreateDate")
+		params.Set("take", strconv.Itoa(createLookupTake))
+		return c.findCreated(ctx, c.baseURL+"/Comments?"+params.Encode(), since, func(item map[string]any) bool {
+			return item["Description"] == description
+		})
+	}
+}
+
+// findCreated returns the item listed at listURL that matches and was
+// created after since, encoded as the create response would have been. More
+// than one such item is an error: any of them may be someone else's.
+func (c *httpClient) findCreated(ctx context.Context, listURL string, since time.Time, match func(map[string]any) bool) ([]byte, error) {
+	data, err := c.doGet(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	var resp entity.APIResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	var found []map[string]any
+	for _, item := range resp.Items {
+		created, ok := item["CreateDate"].(string)
+		if !ok {
+			continue
+		}
+		if t, ok := entity.ParseDate(created); ok && !t.Before(since) && match(item) {
+			found = append(found, item)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return json.Marshal(found[0])
+	default:
+		return nil, fmt.Errorf("%d matching items were created since the first attempt, so which one it produced is uncertain", len(found))
+	}
+}
+
+// currentUser returns the ID of the user the client's credentials act as
+func (c *httpClient) currentUser(ctx context.Context) (int, error) {
+	c.cacheMu.RLock()
+	id := c.currentUserID
+	c.cacheMu.RUnlock()
+	if id != 0 {
+		return id, nil
+	}
+
+	data, err := c.doGet(ctx, c.baseURL+"/Users/LoggedUser")
+	if err != nil {
+		return 0, err
+	}
+	var user entity.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		return 0, err
+	}
+	if user.ID == 0 {
+		return 0, fmt.Errorf("unexpected logged user response")
+	}
+	c.cacheMu.Lock()
+	c.currentUserID = user.ID
+	c.cacheMu.Unlock()
+	return user.ID, nil
+}
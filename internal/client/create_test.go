@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// tpDate formats t the way the TP API does
+func tpDate(t time.Time) string {
+	return fmt.Sprintf("/Date(%d+0000)/", t.UnixMilli())
+}
+
+// createServer fails the first POST with postStatus and answers lookups,
+// made as user 5, with items
+func createServer(t *testing.T, postStatus int, items []map[string]any) (*httptest.Server, *int, *int) {
+	t.Helper()
+	server, posts, gets, _ := createServerWithLookups(t, postStatus, items)
+	return server, posts, gets
+}
+
+// createServerWithLookups is createServer, also recording the where clause
+// of each lookup
+func createServerWithLookups(t *testing.T, postStatus int, items []map[string]any) (*httptest.Server, *int, *int, *[]string) {
+	t.Helper()
+	posts, gets := 0, 0
+	var wheres []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/Users/LoggedUser":
+			w.Write([]byte(`{"Id": 5, "FirstName": "Service"}`))
+		case r.Method == http.MethodPost:
+			posts++
+			if posts == 1 {
+				w.WriteHeader(postStatus)
+				return
+			}
+			w.Write([]byte(`{"Id": 2, "Name": "Login fails"}`))
+		case r.Method == http.MethodGet:
+			gets++
+			wheres = append(wheres, r.URL.Query().Get("where"))
+			json.NewEncoder(w).Encode(map[string]any{"Items": items})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &posts, &gets, &wheres
+}
+
+func TestCreateEntity_LookupFindsLandedCreate(t *testing.T) {
+	server, posts, gets := createServer(t, http.StatusBadGateway, []map[string]any{
+		{"Id": 1, "Name": "Login fails", "CreateDate": tpDate(time.Now())},
+	})
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	result, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Name": "Login fails"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *posts != 1 || *gets != 1 {
+		t.Errorf("expected 1 POST and 1 lookup, got %d and %d", *posts, *gets)
+	}
+	if result["Id"] != float64(1) {
+		t.Errorf("expected the entity found by the lookup, got %v", result)
+	}
+}
+
+func TestCreateEntity_LookupMissRetries(t *testing.T) {
+	server, posts, gets := createServer(t, http.StatusBadGateway, []map[string]any{
+		{"Id": 1, "Name": "Login fails", "CreateDate": tpDate(time.Now().Add(-time.Hour))},
+	})
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	result, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Name": "Login fails"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *posts != 2 || *gets != 1 {
+		t.Errorf("expected 2 POSTs and 1 lookup, got %d and %d", *posts, *gets)
+	}
+	if result["Id"] != float64(2) {
+		t.Errorf("expected the entity from the second POST, got %v", result)
+	}
+}
+
+func TestCreateEntity_LookupMatchesEveryReference(t *testing.T) {
+	server, _, _, wheres := createServerWithLookups(t, http.StatusBadGateway, nil)
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	data := map[string]any{
+		"Name":         "Login fails",
+		"Project":      map[string]any{"Id": float64(12)},
+		"Team":         map[string]any{"Id": 3},
+		"AssignedUser": map[string]any{"Id": 9},
+	}
+	if _, err := c.CreateEntity(context.Background(), entity.TypeBug, data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Name eq 'Login fails' and Owner.Id eq 5 and Project.Id eq 12 and Team.Id eq 3"
+	if len(*wheres) != 1 || (*wheres)[0] != want {
+		t.Errorf("expected lookup where %q, got %q", want, *wheres)
+	}
+}
+
+func TestCreateEntity_AmbiguousLookupIsNotResolved(t *testing.T) {
+	server, posts, _ := createServer(t, http.StatusBadGateway, []map[string]any{
+		{"Id": 3, "Name": "Login fails", "CreateDate": tpDate(time.Now())},
+		{"Id": 1, "Name": "Login fails", "CreateDate": tpDate(time.Now())},
+	})
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	_, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Name": "Login fails"})
+	if err == nil || !strings.Contains(err.Error(), "uncertain") || !strings.Contains(err.Error(), "verify before creating again") {
+		t.Fatalf("expected an error asking to verify, got %v", err)
+	}
+	if *posts != 1 {
+		t.Errorf("expected 1 POST, got %d", *posts)
+	}
+}
+
+func TestCreateEntity_LookupIgnoresEntitiesFromBeforeTheFirstAttempt(t *testing.T) {
+	server, posts, _ := createServer(t, http.StatusBadGateway, []map[string]any{
+		{"Id": 1, "Name": "Login fails", "CreateDate": tpDate(time.Now().Add(-time.Minute))},
+	})
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	result, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Name": "Login fails"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *posts != 2 || result["Id"] != float64(2) {
+		t.Errorf("expected the create to be retried, got %v after %d POSTs", result, *posts)
+	}
+}
+
+func TestCreateEntity_RejectedRequestRetriesWithoutLookup(t *testing.T) {
+	server, posts, gets := createServer(t, http.StatusTooManyRequests, nil)
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	if _, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Name": "Login fails"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *posts != 2 || *gets != 0 {
+		t.Errorf("expected 2 POSTs and no lookup, got %d and %d", *posts, *gets)
+	}
+}
+
+func TestCreateEntity_UnverifiableCreateIsNotRepeated(t *testing.T) {
+	server, posts, _ := createServer(t, http.StatusBadGateway, nil)
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	_, err := c.CreateEntity(context.Background(), entity.TypeBug, map[string]any{"Description": "no name"})
+	if err == nil || !strings.Contains(err.Error(), "verify before creating again") {
+		t.Fatalf("expected an error asking to verify, got %v", err)
+	}
+	if *posts != 1 {
+		t.Errorf("expected 1 POST, got %d", *posts)
+	}
+}
+
+func TestCreateComment_LookupFindsLandedCreate(t *testing.T) {
+	server, posts, _ := createServer(t, http.StatusGatewayTimeout, []map[string]any{
+		{"Id": 7, "Description": "Other", "CreateDate": tpDate(time.Now())},
+		{"Id": 8, "Description": "Fixed in PR #456", "CreateDate": tpDate(time.Now())},
+	})
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	comment, err := c.CreateComment(context.Background(), 100, "Fixed in PR #456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *posts != 1 || comment.ID != 8 {
+		t.Errorf("expected comment 8 after 1 POST, got comment %d after %d", comment.ID, *posts)
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+// Diagnostics describes the connection to one Target Process instance
+type Diagnostics struct {
+	Instance string `json:"instance,omitempty"`
+	Domain   string `json:"domain"`
+	// CircuitBreaker is nil when the breaker is disabled
+	CircuitBreaker *BreakerStatus `json:"circuitBreaker,omitempty"`
+}
+
+// DiagnosticsReporter is implemented by clients that can describe their connections
+type DiagnosticsReporter interface {
+	Diagnostics() []Diagnostics
+}
+
+// Diagnostics reports the state of the client's circuit breaker
+func (c *httpClient) Diagnostics() []Diagnostics {
+	return []Diagnostics{{Domain: c.domain, CircuitBreaker: c.breaker.status()}}
+}
+
+// Diagnostics reports every instance in the order of Instances
+func (r *contextRouter) Diagnostics() []Diagnostics {
+	var out []Diagnostics
+	for _, inst := range append([]Instance{r.def}, r.others...) {
+		reporter, ok := inst.Client.(DiagnosticsReporter)
+		if !ok {
+			out = append(out, Diagnostics{Instance: inst.Name, Domain: inst.Domain})
+			continue
+		}
+		for _, d := range reporter.Diagnostics() {
+			d.Instance = inst.Name
+			out = append(out, d)
+		}
+	}
+	return out
+}
@@ -3,10 +3,12 @@ package client
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"strings"
 
 	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/errors"
 )
 
 // GetEntity retrieves an entity by type and ID
@@ -15,7 +17,7 @@ func (c *httpClient) GetEntity(ctx context.Context, entityType entity.Type, id i
 	if len(include) > 0 {
 		url += fmt.Sprintf("?include=[%s]", strings.Join(include, ","))
 	}
-	data, err := c.doGet(ctx, url)
+	data, err := c.cachedGet(ctx, url, c.cacheTTL.EntityTTL, entityTag(id))
 	if err != nil {
 		return nil, err
 	}
@@ -28,8 +30,12 @@ func (c *httpClient) GetEntity(ctx context.Context, entityType entity.Type, id i
 
 // CreateEntity creates a new entity
 func (c *httpClient) CreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
-	url := c.buildURL(entityType)
-	respData, err := c.doPost(ctx, url, data)
+	req, err := c.PreviewCreateEntity(ctx, entityType, data)
+	if err != nil {
+		return nil, err
+	}
+	respData, err := c.doCreate(ctx, req.URL, req.Body, c.lookupCreatedEntity(entityType, data))
+	c.invalidateEntities(touchedEntities(data)...)
 	if err != nil {
 		return nil, err
 	}
@@ -42,8 +48,12 @@ func (c *httpClient) CreateEntity(ctx context.Context, entityType entity.Type, d
 
 // UpdateEntity updates an existing entity
 func (c *httpClient) UpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
-	url := fmt.Sprintf("%s/%d", c.buildURL(entityType), id)
-	respData, err := c.doPost(ctx, url, data)
+	req, err := c.PreviewUpdateEntity(ctx, entityType, id, data)
+	if err != nil {
+		return nil, err
+	}
+	respData, err := c.doPost(ctx, req.URL, req.Body)
+	c.invalidateEntities(append(touchedEntities(data), id)...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,5 +61,32 @@ func (c *httpClient) UpdateEntity(ctx context.Context, entityType entity.Type, i
 	if err := json.Unmarshal(respData, &result); err != nil {
 		return nil, err
 	}
+	// A team assignment's state shows on the entity it assigns
+	if assignable, ok := result["Assignable"].(map[string]any); ok {
+		if assignableID, ok := intField(assignable["Id"]); ok {
+			c.invalidateEntities(assignableID)
+		}
+	}
 	return result, nil
 }
+
+// DeleteEntity deletes an entity. A retry that finds the entity gone after
+// a failure TP may have processed counts as success.
+func (c *httpClient) DeleteEntity(ctx context.Context, entityType entity.Type, id int) error {
+	req, err := c.PreviewDeleteEntity(ctx, entityType, id)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	_, err = executeWithRetry(ctx, func() ([]byte, error) {
+		data, err := c.send(ctx, req.Method, req.URL, nil)
+		var notFound *errors.NotFoundError
+		if lastErr != nil && mayHaveLanded(lastErr) && stderrors.As(err, &notFound) {
+			return nil, nil
+		}
+		lastErr = err
+		return data, err
+	}, c.retryConfig)
+	c.invalidateEntities(id)
+	return err
+}
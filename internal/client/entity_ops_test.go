@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// deleteServer answers DELETE requests with statuses in turn
+func deleteServer(t *testing.T, statuses ...int) (*httptest.Server, *int) {
+	t.Helper()
+	deletes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/Bugs/42" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(statuses[deletes])
+		deletes++
+	}))
+	t.Cleanup(server.Close)
+	return server, &deletes
+}
+
+func TestDeleteEntity(t *testing.T) {
+	server, deletes := deleteServer(t, http.StatusOK)
+	c := newTestClient(server.URL)
+
+	if err := c.DeleteEntity(context.Background(), entity.TypeBug, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *deletes != 1 {
+		t.Errorf("expected 1 DELETE, got %d", *deletes)
+	}
+}
+
+func TestDeleteEntity_NotFoundAfterLandedFailureSucceeds(t *testing.T) {
+	server, deletes := deleteServer(t, http.StatusBadGateway, http.StatusNotFound)
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 2
+
+	if err := c.DeleteEntity(context.Background(), entity.TypeBug, 42); err != nil {
+		t.Fatalf("expected the retry's 404 to confirm the delete, got %v", err)
+	}
+	if *deletes != 2 {
+		t.Errorf("expected 2 DELETEs, got %d", *deletes)
+	}
+}
+
+func TestDeleteEntity_NotFoundFails(t *testing.T) {
+	server, _ := deleteServer(t, http.StatusNotFound)
+	c := newTestClient(server.URL)
+
+	if err := c.DeleteEntity(context.Background(), entity.TypeBug, 42); err == nil {
+		t.Fatal("expected an error deleting a missing entity")
+	}
+}
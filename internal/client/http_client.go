@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,86 +18,177 @@ import (
 )
 
 type httpClient struct {
+	domain      string
 	baseURL     string
 	httpClient  *http.Client
 	auth        auth.Strategy
 	retryConfig config.RetryConfig
+	limiter     *rateLimiter
+	breaker     *circuitBreaker
 	token       string
 
+	// cache holds GET responses; nil when caching is disabled
+	cache    *responseCache
+	cacheTTL config.CacheConfig
+
 	// Entity type cache
 	cacheMu     sync.RWMutex
 	cachedTypes []string
 	cacheExpiry time.Time
+	// resources holds the collection names read from metadata, shared by
+	// the clients of the instance
+	resources *resourceNames
+	// currentUserID is the ID of the user the credentials act as, once
+	// known; guarded by cacheMu
+	currentUserID int
 }
 
-// NewHTTPClient creates a new Client implementation
-func NewHTTPClient(cfg *config.Config, authStrategy auth.Strategy) Client {
-	return &httpClient{
-		baseURL:     fmt.Sprintf("https://%s/api/v1", cfg.Domain),
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+// NewHTTPClient creates a new Client implementation, caching GET responses
+// when cfg.Cache is enabled. It fails when the CA bundle or client
+// certificate of cfg.Endpoint cannot be loaded.
+func NewHTTPClient(cfg *config.Config, authStrategy auth.Strategy) (Client, error) {
+	transport, err := sharedTransport(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := apiBaseURL(cfg.Domain, cfg.Endpoint)
+	c := &httpClient{
+		domain:      cfg.Domain,
+		baseURL:     baseURL,
+		httpClient:  &http.Client{Timeout: cfg.Timeouts.HTTP, Transport: transport},
 		auth:        authStrategy,
 		retryConfig: cfg.Retry,
+		limiter:     sharedLimiter(cfg.Domain, cfg.RateLimit),
+		breaker:     sharedBreaker(cfg.Domain, cfg.Breaker),
 		token:       cfg.AccessToken,
+		cacheTTL:    cfg.Cache,
+		resources:   sharedResourceNames(baseURL),
+	}
+	// Each client caches on its own, so a session acting with its own token
+	// never sees responses fetched with another
+	if cfg.Cache.Enabled {
+		c.cache = newResponseCache(cfg.Cache.MaxEntries)
 	}
+	return c, nil
 }
 
 // buildURL constructs the API URL for an entity type
 func (c *httpClient) buildURL(entityType entity.Type) string {
-	return fmt.Sprintf("%s/%s", c.baseURL, entity.Pluralize(entityType))
+	return fmt.Sprintf("%s/%s", c.baseURL, c.resourceName(entityType))
 }
 
-// doRequest executes an HTTP request with auth and retry
+// doRequest executes an idempotent HTTP request with auth, rate limiting and
+// retry. Creates must go through doCreate instead, which never re-sends a
+// request that may already have been processed.
 func (c *httpClient) doRequest(ctx context.Context, method, url string, body any) ([]byte, error) {
-	return executeWithRetry(func() ([]byte, error) {
-		var bodyReader io.Reader
-		if body != nil {
-			jsonBytes, err := json.Marshal(body)
-			if err != nil {
-				return nil, err
-			}
-			bodyReader = bytes.NewReader(jsonBytes)
-		}
+	return executeWithRetry(ctx, func() ([]byte, error) {
+		return c.send(ctx, method, url, body)
+	}, c.retryConfig)
+}
 
-		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-		if err != nil {
-			return nil, err
-		}
+// send makes a single attempt at an HTTP request, unless the circuit breaker
+// is open, and reports the outcome to the breaker
+func (c *httpClient) send(ctx context.Context, method, url string, body any) ([]byte, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	token := c.secret()
+	data, err := c.transmit(ctx, method, url, body)
+	// TP rejected the request, so re-sending it is safe once the token is fresh
+	if isUnauthorized(err) && c.refreshToken(ctx, token) {
+		data, err = c.transmit(ctx, method, url, body)
+	}
+	c.breaker.record(ctx, err)
+	return data, err
+}
 
-		if body != nil {
-			req.Header.Set("Content-Type", "application/json")
-		}
+// refreshToken re-reads a rotating token after TP rejected the stale one,
+// reporting whether a different token is now in use
+func (c *httpClient) refreshToken(ctx context.Context, stale string) bool {
+	rotator, ok := c.auth.(auth.Rotator)
+	if !ok {
+		return false
+	}
+	changed, err := rotator.Refresh(ctx, stale)
+	return err == nil && changed
+}
 
-		// Add format=json query param
-		q := req.URL.Query()
-		q.Set("format", "json")
-		req.URL.RawQuery = q.Encode()
+// secret returns the token to mask in error messages: the current one when
+// it rotates
+func (c *httpClient) secret() string {
+	if rotator, ok := c.auth.(auth.Rotator); ok {
+		return rotator.Token()
+	}
+	return c.token
+}
 
-		// Apply auth (adds access_token param)
-		c.auth.ApplyAuth(req)
+// isUnauthorized reports whether err is a 401 response
+func isUnauthorized(err error) bool {
+	var apiErr *errors.APIError
+	return stderrors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
+// transmit sends an HTTP request with auth and rate limiting
+func (c *httpClient) transmit(ctx context.Context, method, url string, body any) ([]byte, error) {
+	if err := c.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
 
-		respBody, err := io.ReadAll(resp.Body)
+	var bodyReader io.Reader
+	if body != nil {
+		jsonBytes, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
+		bodyReader = bytes.NewReader(jsonBytes)
+	}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			maskedBody := errors.MaskToken(string(respBody), c.token)
-			return nil, &errors.APIError{
-				StatusCode: resp.StatusCode,
-				Message:    errors.ParseTPErrorBody(maskedBody),
-				RawBody:    maskedBody,
-				Context:    fmt.Sprintf("%s %s", method, errors.MaskToken(url, c.token)),
-			}
-		}
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	// Add format=json query param
+	q := req.URL.Query()
+	q.Set("format", "json")
+	req.URL.RawQuery = q.Encode()
+
+	// Apply auth (adds access_token param)
+	c.auth.ApplyAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, c.apiError(resp, respBody, method, url)
+	}
+
+	return respBody, nil
+}
 
-		return respBody, nil
-	}, c.retryConfig.MaxRetries, c.retryConfig.InitialDelay, c.retryConfig.BackoffFactor)
+// apiError builds the typed error for a non-success response, masking the
+// token in its body and URL
+func (c *httpClient) apiError(resp *http.Response, body []byte, method, url string) error {
+	maskedBody := errors.MaskToken(string(body), c.secret())
+	return errors.Classify(&errors.APIError{
+		StatusCode: resp.StatusCode,
+		Message:    errors.ParseTPErrorBody(maskedBody),
+		RawBody:    maskedBody,
+		Context:    fmt.Sprintf("%s %s", method, errors.MaskToken(url, c.secret())),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	})
 }
 
 // doGet performs a GET request
@@ -104,7 +196,8 @@ func (c *httpClient) doGet(ctx context.Context, url string) ([]byte, error) {
 	return c.doRequest(ctx, http.MethodGet, url, nil)
 }
 
-// doPost performs a POST request with JSON body
+// doPost performs a POST request with JSON body; it is retried, so use it
+// only for idempotent requests such as updates
 func (c *httpClient) doPost(ctx context.Context, url string, body any) ([]byte, error) {
 	return c.doRequest(ctx, http.MethodPost, url, body)
 }
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/errors"
+)
+
+func TestSend_RefreshesRotatedTokenOn401(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("access_token")
+		seen = append(seen, token)
+		if token != "new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("token " + token + " expired"))
+			return
+		}
+		w.Write([]byte(`{"Id": 1}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("old-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	strategy, err := auth.NewRotatingStrategy(context.Background(), auth.FileTokenSource(path), config.AuthAccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(server.URL)
+	c.auth = strategy
+
+	// The secrets agent rotates the token behind the server's back
+	if err := os.WriteFile(path, []byte("new-token"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.doGet(context.Background(), c.baseURL+"/UserStories/1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "old-token" || seen[1] != "new-token" {
+		t.Errorf("expected a retry with the new token, got %q", seen)
+	}
+}
+
+func TestSend_MasksCurrentRotatedToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("token " + r.URL.Query().Get("access_token") + " expired"))
+	}))
+	defer server.Close()
+
+	source := func(ctx context.Context) (string, error) { return "rotating-secret", nil }
+	strategy, err := auth.NewRotatingStrategy(context.Background(), source, config.AuthAccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(server.URL)
+	c.auth = strategy
+
+	_, err = c.doGet(context.Background(), c.baseURL+"/UserStories/1")
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 APIError, got %v", err)
+	}
+	if strings.Contains(apiErr.RawBody, "rotating-secret") || strings.Contains(apiErr.Context, "rotating-secret") {
+		t.Errorf("expected the rotated token to be masked, got body %q and context %q", apiErr.RawBody, apiErr.Context)
+	}
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
 )
 
 // FetchMetadata fetches the TP API metadata
 func (c *httpClient) FetchMetadata(ctx context.Context) (any, error) {
 	url := fmt.Sprintf("%s/Index/meta", c.baseURL)
-	data, err := c.doGet(ctx, url)
+	data, err := c.cachedGet(ctx, url, c.cacheTTL.MetadataTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -20,14 +23,103 @@ func (c *httpClient) FetchMetadata(ctx context.Context) (any, error) {
 	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, err
 	}
+	c.learnResourceNames(result)
 	return result, nil
 }
 
+// GetCustomFields returns the custom fields every process defines for
+// entityType
+func (c *httpClient) GetCustomFields(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+	params := url.Values{}
+	params.Set("where", query.FormatStringCondition("EntityType.Name", "eq", string(entityType)))
+	params.Set("include", "[Name,FieldType,Value,Required,Process]")
+	params.Set("take", "1000")
+	data, err := c.cachedGet(ctx, fmt.Sprintf("%s/CustomFields?%s", c.baseURL, params.Encode()), c.cacheTTL.MetadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Items []struct {
+			Name      string `json:"Name"`
+			FieldType string `json:"FieldType"`
+			// Value holds a list field's options, one per line
+			Value    *string     `json:"Value"`
+			Required bool        `json:"Required"`
+			Process  *entity.Ref `json:"Process"`
+		} `json:"Items"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("unexpected custom fields response: %w", err)
+	}
+	defs := make([]entity.CustomFieldDefinition, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		def := entity.CustomFieldDefinition{Name: item.Name, Type: item.FieldType, Required: item.Required}
+		if item.Process != nil {
+			def.ProcessID, def.ProcessName = item.Process.ID, item.Process.Name
+		}
+		if item.Value != nil && (item.FieldType == "DropDown" || item.FieldType == "MultipleSelectionList") {
+			for _, option := range strings.Split(*item.Value, "\n") {
+				if option = strings.TrimSpace(option); option != "" {
+					def.Options = append(def.Options, option)
+				}
+			}
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// GetEntityStates returns the workflow states, with their allowed
+// transitions, that the process with the given ID defines for entityType
+func (c *httpClient) GetEntityStates(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error) {
+	params := url.Values{}
+	params.Set("where", query.FormatNumberCondition("Process.Id", "eq", processID)+" and "+
+		query.FormatStringCondition("EntityType.Name", "eq", string(entityType)))
+	params.Set("include", "[Name,IsInitial,IsFinal,NumericPriority,NextStates[Id],ParentEntityState[Id],Workflow[Id]]")
+	params.Set("orderBy", "NumericPriority")
+	params.Set("take", "1000")
+	data, err := c.cachedGet(ctx, fmt.Sprintf("%s/EntityStates?%s", c.baseURL, params.Encode()), c.cacheTTL.MetadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Items []struct {
+			ID                int                           `json:"Id"`
+			Name              string                        `json:"Name"`
+			IsInitial         bool                          `json:"IsInitial"`
+			IsFinal           bool                          `json:"IsFinal"`
+			NextStates        entity.Collection[entity.Ref] `json:"NextStates"`
+			ParentEntityState *entity.Ref                   `json:"ParentEntityState"`
+			Workflow          *entity.Ref                   `json:"Workflow"`
+		} `json:"Items"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("unexpected entity states response: %w", err)
+	}
+	states := make([]entity.WorkflowState, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		s := entity.WorkflowState{ID: item.ID, Name: item.Name, IsInitial: item.IsInitial, IsFinal: item.IsFinal, NextStates: []int{}}
+		for _, next := range item.NextStates.Items {
+			s.NextStates = append(s.NextStates, next.ID)
+		}
+		if item.ParentEntityState != nil {
+			s.ParentID = item.ParentEntityState.ID
+		}
+		if item.Workflow != nil {
+			s.WorkflowID = item.Workflow.ID
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
 // GetValidEntityTypes returns cached entity types or fetches from API
 func (c *httpClient) GetValidEntityTypes(ctx context.Context) ([]string, error) {
 	// Check cache first
 	c.cacheMu.RLock()
-	if c.cachedTypes != nil && time.Now().Before(c.cacheExpiry) {
+	if c.cachedTypes != nil && time.Now().Before(c.cacheExpiry) && !cacheBypassed(ctx) {
 		types := make([]string, len(c.cachedTypes))
 		copy(types, c.cachedTypes)
 		c.cacheMu.RUnlock()
@@ -47,6 +139,7 @@ func (c *httpClient) GetValidEntityTypes(ctx context.Context) ([]string, error)
 	if len(types) == 0 {
 		return staticEntityTypes(), nil
 	}
+	entity.RegisterTypes(types)
 
 	// Update cache
 	c.cacheMu.Lock()
@@ -57,23 +150,20 @@ func (c *httpClient) GetValidEntityTypes(ctx context.Context) ([]string, error)
 	return types, nil
 }
 
-// InitializeCache pre-populates the entity type cache
+// InitializeCache pre-populates the entity type cache, registering the
+// instance's entity types with the entity package
 func (c *httpClient) InitializeCache(ctx context.Context) error {
 	_, err := c.GetValidEntityTypes(ctx)
 	return err
 }
 
-// extractEntityTypes pulls entity type names from the metadata response
+// extractEntityTypes pulls entity type names from the metadata response.
+// Only the resources it describes with a /meta URI count, so other keys of
+// the response, such as Next or Links, are not taken for types.
 func extractEntityTypes(metadata any) []string {
-	// Metadata is expected to be a map with entity type info
-	metaMap, ok := metadata.(map[string]any)
-	if !ok {
-		return nil
-	}
-
 	var types []string
-	for key := range metaMap {
-		types = append(types, key)
+	for name := range extractResourceNames(metadata) {
+		types = append(types, string(name))
 	}
 	return types
 }
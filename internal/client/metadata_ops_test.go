@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+func TestGetCustomFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/CustomFields" || r.URL.Query().Get("where") != "EntityType.Name eq 'Bug'" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Write([]byte(`{"Items": [
+			{"Name": "Risk", "FieldType": "DropDown", "Value": "Low\r\nHigh\r\n", "Required": true, "Process": {"Id": 3, "Name": "Scrum"}},
+			{"Name": "Ticket", "FieldType": "Text", "Value": null, "Required": false}
+		]}`))
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+
+	defs, err := c.GetCustomFields(context.Background(), entity.TypeBug)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(defs) != 2 {
+		t.Fatalf("expected 2 definitions, got %+v", defs)
+	}
+	risk := defs[0]
+	if risk.Name != "Risk" || risk.Type != "DropDown" || !risk.Required || risk.ProcessID != 3 || risk.ProcessName != "Scrum" {
+		t.Errorf("unexpected definition: %+v", risk)
+	}
+	if len(risk.Options) != 2 || risk.Options[0] != "Low" || risk.Options[1] != "High" {
+		t.Errorf("expected options Low and High, got %q", risk.Options)
+	}
+	if defs[1].Options != nil || defs[1].ProcessID != 0 {
+		t.Errorf("unexpected definition: %+v", defs[1])
+	}
+}
+
+func TestGetEntityStates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/EntityStates" || r.URL.Query().Get("where") != "Process.Id eq 3 and EntityType.Name eq 'Bug'" {
+			t.Errorf("unexpected request: %s", r.URL)
+		}
+		w.Write([]byte(`{"Items": [
+			{"Id": 1, "Name": "Open", "IsInitial": true, "NextStates": {"Items": [{"Id": 2}]}, "ParentEntityState": null},
+			{"Id": 2, "Name": "Done", "IsFinal": true, "NextStates": {"Items": []}},
+			{"Id": 7, "Name": "Testing", "NextStates": {"Items": []}, "ParentEntityState": {"Id": 1}, "Workflow": {"Id": 9}}
+		]}`))
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+
+	states, err := c.GetEntityStates(context.Background(), entity.TypeBug, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(states) != 3 {
+		t.Fatalf("expected 3 states, got %+v", states)
+	}
+	if open := states[0]; open.Name != "Open" || !open.IsInitial || len(open.NextStates) != 1 || open.NextStates[0] != 2 {
+		t.Errorf("unexpected state: %+v", open)
+	}
+	if !states[1].IsFinal || states[1].NextStates == nil {
+		t.Errorf("expected a final state with no transitions, got %+v", states[1])
+	}
+	if states[2].ParentID != 1 || states[2].WorkflowID != 9 {
+		t.Errorf("expected a team sub-state of state 1, got %+v", states[2])
+	}
+}
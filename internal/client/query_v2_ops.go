@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"tp-mcp-go/internal/domain/query"
+)
+
+// v2Response is the envelope of a v2 API collection
+type v2Response struct {
+	Items []map[string]any `json:"items"`
+	Next  string           `json:"next"`
+}
+
+// QueryV2 runs a query against the v2 API, which shares the v1 API's host and auth
+func (c *httpClient) QueryV2(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error) {
+	fullURL := req.Cursor
+	if fullURL != "" {
+		if err := validateURL(fullURL, c.baseURL); err != nil {
+			return nil, err
+		}
+	} else {
+		params := url.Values{}
+		if req.Select != "" {
+			params.Set("select", req.Select)
+		}
+		if req.Where != "" {
+			params.Set("where", req.Where)
+		}
+		if req.OrderBy != "" {
+			params.Set("orderBy", req.OrderBy)
+		}
+		if req.Take > 0 {
+			params.Set("take", strconv.Itoa(req.Take))
+		}
+		if req.Skip > 0 {
+			params.Set("skip", strconv.Itoa(req.Skip))
+		}
+		fullURL = c.v2BaseURL() + "/" + string(req.EntityType)
+		if len(params) > 0 {
+			fullURL += "?" + params.Encode()
+		}
+	}
+
+	data, err := c.doGet(ctx, fullURL)
+	if err != nil {
+		return nil, err
+	}
+	var resp v2Response
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &query.PaginatedResponse{
+		Items: resp.Items,
+		Pagination: query.PaginationMeta{
+			HasMore:  resp.Next != "",
+			Cursor:   resp.Next,
+			Returned: len(resp.Items),
+		},
+	}, nil
+}
+
+// v2BaseURL is the root of the v2 API, next to the v1 API
+func (c *httpClient) v2BaseURL() string {
+	return strings.TrimSuffix(c.baseURL, "/api/v1") + "/api/v2"
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
+)
+
+func TestQueryV2_BuildsURL(t *testing.T) {
+	var captured *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r
+		w.Write([]byte(`{"next":"http://` + r.Host + `/api/v2/UserStory?skip=2&take=2","items":[{"id":1,"openTasks":3},{"id":2,"openTasks":0}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	resp, err := c.QueryV2(context.Background(), query.V2Request{
+		EntityType: entity.TypeUserStory,
+		Select:     "{id,openTasks:tasks.where(entityState.isFinal==false).count}",
+		Where:      "project.id==10",
+		OrderBy:    "id desc",
+		Take:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if captured.URL.Path != "/api/v2/UserStory" {
+		t.Errorf("path = %q, want /api/v2/UserStory", captured.URL.Path)
+	}
+	q := captured.URL.Query()
+	if q.Get("select") != "{id,openTasks:tasks.where(entityState.isFinal==false).count}" || q.Get("where") != "project.id==10" ||
+		q.Get("orderBy") != "id desc" || q.Get("take") != "2" || q.Has("skip") {
+		t.Errorf("unexpected query: %s", captured.URL.RawQuery)
+	}
+	if q.Get("access_token") != "test-token" {
+		t.Error("expected auth to be applied to v2 requests")
+	}
+	if len(resp.Items) != 2 || !resp.Pagination.HasMore || resp.Pagination.Returned != 2 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestQueryV2_RejectsForeignCursor(t *testing.T) {
+	c := newTestClient("https://test.tpondemand.com")
+	_, err := c.QueryV2(context.Background(), query.V2Request{Cursor: "https://evil.example.com/api/v2/UserStory"})
+	if err == nil {
+		t.Fatal("expected the cursor to be rejected")
+	}
+}
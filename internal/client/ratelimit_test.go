@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/errors"
+)
+
+func TestRateLimiter_AllowsBurstThenThrottles(t *testing.T) {
+	l := newRateLimiter(config.RateLimitConfig{RequestsPerSecond: 20, Burst: 3})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(ctx); err != nil {
+			t.Fatalf("wait %d: unexpected error: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("burst took %s, want it immediate", elapsed)
+	}
+
+	start = time.Now()
+	if err := l.wait(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("request past the burst waited %s, want about 50ms", elapsed)
+	}
+}
+
+func TestRateLimiter_WaitHonorsContext(t *testing.T) {
+	l := newRateLimiter(config.RateLimitConfig{RequestsPerSecond: 0.1, Burst: 1})
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestSharedLimiter(t *testing.T) {
+	cfg := config.RateLimitConfig{RequestsPerSecond: 5, Burst: 5}
+	if sharedLimiter("a.tpondemand.com", cfg) != sharedLimiter("a.tpondemand.com", cfg) {
+		t.Error("expected clients for the same domain to share a limiter")
+	}
+	if sharedLimiter("a.tpondemand.com", cfg) == sharedLimiter("b.tpondemand.com", cfg) {
+		t.Error("expected each domain to have its own limiter")
+	}
+	if sharedLimiter("a.tpondemand.com", config.RateLimitConfig{}) != nil {
+		t.Error("expected no limiter when the rate is zero")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"7", 7 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := config.RetryConfig{InitialDelay: time.Second, BackoffFactor: 2, MaxDelay: 10 * time.Second}
+	plain := &errors.APIError{StatusCode: 503}
+
+	if got := retryDelay(policy, 2, plain); got != 4*time.Second {
+		t.Errorf("backoff delay = %s, want 4s", got)
+	}
+	if got := retryDelay(policy, 0, &errors.APIError{StatusCode: 429, RetryAfter: 5 * time.Second}); got != 5*time.Second {
+		t.Errorf("Retry-After delay = %s, want 5s", got)
+	}
+	if got := retryDelay(policy, 0, &errors.APIError{StatusCode: 429, RetryAfter: time.Hour}); got != 10*time.Second {
+		t.Errorf("capped delay = %s, want 10s", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := retryDelay(policy, 0, plain); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("jittered delay = %s, want within 50%% of 1s", got)
+		}
+	}
+}
+
+func TestDoRequest_RetriesAfterRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write(emptyAPIResponse())
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.retryConfig.MaxRetries = 1
+	c.limiter = newRateLimiter(config.RateLimitConfig{RequestsPerSecond: 100, Burst: 1})
+
+	if _, err := c.doGet(context.Background(), server.URL+"/api/v1/Bugs"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
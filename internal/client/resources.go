@@ -0,0 +1,114 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// resourceNames holds the collection names read from an instance's metadata
+type resourceNames struct {
+	mu    sync.RWMutex
+	names map[entity.Type]string
+}
+
+// sharedNames holds one resourceNames per API base URL, so every client for
+// an instance, including per-session clients that never read metadata
+// themselves, follows the names any of them learned
+var sharedNames = struct {
+	sync.Mutex
+	m map[string]*resourceNames
+}{m: make(map[string]*resourceNames)}
+
+// sharedResourceNames returns the resourceNames for baseURL
+func sharedResourceNames(baseURL string) *resourceNames {
+	sharedNames.Lock()
+	defer sharedNames.Unlock()
+	r, ok := sharedNames.m[baseURL]
+	if !ok {
+		r = &resourceNames{}
+		sharedNames.m[baseURL] = r
+	}
+	return r
+}
+
+// resourceName returns the collection name used in URLs for entityType: the
+// one TP's metadata gives it once metadata has been read, the static fallback
+// otherwise
+func (c *httpClient) resourceName(entityType entity.Type) string {
+	if c.resources != nil {
+		c.resources.mu.RLock()
+		name, ok := c.resources.names[entityType]
+		c.resources.mu.RUnlock()
+		if ok {
+			return name
+		}
+	}
+	return entity.ResourceName(entityType)
+}
+
+// learnResourceNames records the collection names found in metadata, so
+// later URLs follow what this TP instance actually serves
+func (c *httpClient) learnResourceNames(metadata any) {
+	names := extractResourceNames(metadata)
+	if len(names) == 0 || c.resources == nil {
+		return
+	}
+	c.resources.mu.Lock()
+	c.resources.names = names
+	c.resources.mu.Unlock()
+}
+
+// extractResourceNames maps entity type names to collection names in the
+// /Index/meta response. Each resource is described by its Name and a Uri
+// ending in /{Collection}/meta, either in an Items list or keyed by name.
+func extractResourceNames(metadata any) map[entity.Type]string {
+	metaMap, ok := metadata.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var resources []any
+	if items, ok := metaMap["Items"].([]any); ok {
+		resources = items
+	} else {
+		for name, v := range metaMap {
+			if res, ok := v.(map[string]any); ok {
+				if _, hasName := res["Name"]; !hasName {
+					res = map[string]any{"Name": name, "Uri": res["Uri"]}
+				}
+				resources = append(resources, res)
+			}
+		}
+	}
+
+	names := make(map[entity.Type]string)
+	for _, r := range resources {
+		res, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := res["Name"].(string)
+		uri, _ := res["Uri"].(string)
+		if collection := collectionFromURI(uri); name != "" && collection != "" {
+			names[entity.Type(name)] = collection
+		}
+	}
+	return names
+}
+
+// collectionFromURI returns Collection from a metadata URI such as
+// https://x.tpondemand.com/api/v1/Collection/meta
+func collectionFromURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-1] != "meta" {
+		return ""
+	}
+	return segments[len(segments)-2]
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+func TestExtractResourceNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata any
+		want     map[entity.Type]string
+	}{
+		{
+			name: "items list",
+			metadata: map[string]any{"Items": []any{
+				map[string]any{"Name": "UserStory", "Uri": "https://x.tpondemand.com/api/v1/UserStories/meta"},
+				map[string]any{"Name": "Objective", "Uri": "https://x.tpondemand.com/api/v1/Objectives/meta"},
+				map[string]any{"Name": "Broken", "Uri": "not a meta uri"},
+			}},
+			want: map[entity.Type]string{"UserStory": "UserStories", "Objective": "Objectives"},
+		},
+		{
+			name: "keyed by name",
+			metadata: map[string]any{
+				"Process": map[string]any{"Uri": "https://tp.corp/tp/api/v1/Processes/meta"},
+			},
+			want: map[entity.Type]string{"Process": "Processes"},
+		},
+		{
+			name:     "unexpected shape",
+			metadata: []any{"UserStory"},
+			want:     map[entity.Type]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractResourceNames(tt.metadata)
+			if len(got) != len(tt.want) {
+				t.Fatalf("extractResourceNames() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("resource of %s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildURL_UsesMetadataNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Items":[{"Name":"Request","Uri":"http://` + r.Host + `/api/v1/CustomerRequests/meta"}]}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	if got := c.buildURL(entity.TypeUserStory); got != server.URL+"/api/v1/UserStories" {
+		t.Errorf("buildURL before metadata = %q, want the static fallback", got)
+	}
+
+	if _, err := c.FetchMetadata(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := c.buildURL(entity.TypeRequest); got != server.URL+"/api/v1/CustomerRequests" {
+		t.Errorf("buildURL = %q, want the collection named by metadata", got)
+	}
+	if got := c.buildURL(entity.TypeBug); got != server.URL+"/api/v1/Bugs" {
+		t.Errorf("buildURL = %q, want the static fallback for a type metadata omits", got)
+	}
+}
+
+func TestResourceNames_SharedByTheClientsOfAnInstance(t *testing.T) {
+	metadata := map[string]any{"Items": []any{map[string]any{"Name": "Request", "Uri": "https://a.tpondemand.com/api/v1/CustomerRequests/meta"}}}
+
+	learner := newTestClient("https://a.tpondemand.com")
+	learner.resources = sharedResourceNames(learner.baseURL)
+	session := newTestClient("https://a.tpondemand.com")
+	session.resources = sharedResourceNames(session.baseURL)
+	other := newTestClient("https://b.tpondemand.com")
+	other.resources = sharedResourceNames(other.baseURL)
+
+	learner.learnResourceNames(metadata)
+	if got := session.resourceName(entity.TypeRequest); got != "CustomerRequests" {
+		t.Errorf("expected a client that never read metadata to use the learned name, got %q", got)
+	}
+	if got := other.resourceName(entity.TypeRequest); got != "Requests" {
+		t.Errorf("expected another instance to keep the static name, got %q", got)
+	}
+}
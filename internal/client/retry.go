@@ -1,30 +1,85 @@
 package client
 
 import (
+	"context"
+	stderrors "errors"
+	"fmt"
 	"math"
+	"math/rand/v2"
 	"time"
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/errors"
 )
 
 // executeWithRetry retries an operation with exponential backoff.
-// It does not retry on 400/401 errors (checked via errors.IsRetryable).
-func executeWithRetry[T any](operation func() (T, error), maxRetries int, initialDelay time.Duration, backoffFactor float64) (T, error) {
+// It does not retry errors errors.IsRetryable reports as final, and stops as
+// soon as ctx is done, including while waiting between attempts.
+func executeWithRetry[T any](ctx context.Context, operation func() (T, error), policy config.RetryConfig) (T, error) {
+	var zero T
 	var lastErr error
-	for attempt := 0; attempt <= maxRetries; attempt++ {
+	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
 		result, err := operation()
 		if err == nil {
 			return result, nil
 		}
 		lastErr = err
+		var final *finalError
+		if stderrors.As(err, &final) {
+			return zero, final.err
+		}
+		// The caller gave up, which every later attempt would hit too; a
+		// request that only hit http.Client.Timeout is retried below
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if stderrors.Is(err, ctxErr) {
+				return zero, err
+			}
+			return zero, fmt.Errorf("%w (last error: %v)", ctxErr, err)
+		}
 		if !errors.IsRetryable(err) {
-			var zero T
 			return zero, err
 		}
-		if attempt < maxRetries {
-			delay := time.Duration(float64(initialDelay) * math.Pow(backoffFactor, float64(attempt)))
-			time.Sleep(delay)
+		if attempt < policy.MaxRetries {
+			if err := sleep(ctx, retryDelay(policy, attempt, err)); err != nil {
+				return zero, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
 		}
 	}
-	var zero T
 	return zero, lastErr
 }
+
+// finalError stops executeWithRetry, which returns the wrapped error as is
+type finalError struct {
+	err error
+}
+
+func (e *finalError) Error() string { return e.err.Error() }
+func (e *finalError) Unwrap() error { return e.err }
+
+// retryDelay is the wait after the given failed attempt: exponential backoff
+// with jitter, stretched to honor a Retry-After header and capped at MaxDelay
+func retryDelay(policy config.RetryConfig, attempt int, err error) time.Duration {
+	delay := float64(policy.InitialDelay) * math.Pow(policy.BackoffFactor, float64(attempt))
+	if policy.Jitter > 0 {
+		delay *= 1 + policy.Jitter*(2*rand.Float64()-1)
+	}
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) && float64(apiErr.RetryAfter) > delay {
+		delay = float64(apiErr.RetryAfter)
+	}
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+	return time.Duration(delay)
+}
+
+// sleep waits for d, returning ctx.Err() early if ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
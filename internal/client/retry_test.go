@@ -1,9 +1,15 @@
 package client
 
 import (
+	"context"
 	stderrors "errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/errors"
 )
 
@@ -15,7 +21,7 @@ func TestSuccessOnFirstTry(t *testing.T) {
 		return "success", nil
 	}
 
-	result, err := executeWithRetry(operation, 3, 0, 2.0)
+	result, err := executeWithRetry(context.Background(), operation, config.RetryConfig{MaxRetries: 3, BackoffFactor: 2.0})
 
 	if err != nil {
 		t.Errorf("expected no error, got: %v", err)
@@ -39,7 +45,7 @@ func TestSuccessOnSecondAttempt(t *testing.T) {
 		return "success", nil
 	}
 
-	result, err := executeWithRetry(operation, 3, 0, 2.0)
+	result, err := executeWithRetry(context.Background(), operation, config.RetryConfig{MaxRetries: 3, BackoffFactor: 2.0})
 
 	if err != nil {
 		t.Errorf("expected no error, got: %v", err)
@@ -60,7 +66,7 @@ func TestNoRetryOn400(t *testing.T) {
 		return "", &errors.APIError{StatusCode: 400, Message: "bad request"}
 	}
 
-	result, err := executeWithRetry(operation, 3, 0, 2.0)
+	result, err := executeWithRetry(context.Background(), operation, config.RetryConfig{MaxRetries: 3, BackoffFactor: 2.0})
 
 	if err == nil {
 		t.Error("expected error, got nil")
@@ -86,7 +92,7 @@ func TestNoRetryOn401(t *testing.T) {
 		return "", &errors.APIError{StatusCode: 401, Message: "unauthorized"}
 	}
 
-	result, err := executeWithRetry(operation, 3, 0, 2.0)
+	result, err := executeWithRetry(context.Background(), operation, config.RetryConfig{MaxRetries: 3, BackoffFactor: 2.0})
 
 	if err == nil {
 		t.Error("expected error, got nil")
@@ -112,7 +118,7 @@ func TestExhaustedRetriesReturnsLastError(t *testing.T) {
 		return "", &errors.APIError{StatusCode: 503, Message: fmt.Sprintf("attempt %d", callCount)}
 	}
 
-	result, err := executeWithRetry(operation, 2, 0, 2.0)
+	result, err := executeWithRetry(context.Background(), operation, config.RetryConfig{MaxRetries: 2, BackoffFactor: 2.0})
 
 	if err == nil {
 		t.Error("expected error, got nil")
@@ -150,7 +156,7 @@ func TestAttemptCount(t *testing.T) {
 				return 0, &errors.APIError{StatusCode: 500, Message: "error"}
 			}
 
-			executeWithRetry(operation, tt.maxRetries, 0, 2.0)
+			executeWithRetry(context.Background(), operation, config.RetryConfig{MaxRetries: tt.maxRetries, BackoffFactor: 2.0})
 
 			if callCount != tt.wantCalls {
 				t.Errorf("expected %d calls, got: %d", tt.wantCalls, callCount)
@@ -158,3 +164,82 @@ func TestAttemptCount(t *testing.T) {
 		})
 	}
 }
+
+// TestCancelledDuringBackoff verifies that a cancelled context ends the wait between attempts
+func TestCancelledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	callCount := 0
+	operation := func() (string, error) {
+		callCount++
+		cancel()
+		return "", &errors.APIError{StatusCode: 503, Message: "unavailable"}
+	}
+
+	start := time.Now()
+	_, err := executeWithRetry(ctx, operation, config.RetryConfig{MaxRetries: 3, InitialDelay: time.Minute, BackoffFactor: 2.0})
+
+	if !stderrors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 call, got: %d", callCount)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected backoff to be abandoned, waited %s", elapsed)
+	}
+}
+
+// TestNoRetryOnContextError verifies that a request failed by its caller's context is not retried
+func TestNoRetryOnContextError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	callCount := 0
+	operation := func() (string, error) {
+		callCount++
+		return "", fmt.Errorf("Get \"https://example.tpondemand.com\": %w", ctx.Err())
+	}
+
+	_, err := executeWithRetry(ctx, operation, config.RetryConfig{MaxRetries: 3, BackoffFactor: 2.0})
+
+	if !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if callCount != 1 {
+		t.Errorf("expected 1 call (no retry), got: %d", callCount)
+	}
+}
+
+// TestRetriesClientTimeout verifies that a request that hit http.Client.Timeout
+// while the caller still waits is retried and counted by the circuit breaker
+func TestRetriesClientTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Write([]byte(`{"Id":1}`))
+	}))
+	defer server.Close()
+
+	c := newTestClient(server.URL)
+	c.httpClient.Timeout = 50 * time.Millisecond
+	c.retryConfig = config.RetryConfig{MaxRetries: 1, BackoffFactor: 1.0}
+	c.breaker = newCircuitBreaker("example.tpondemand.com", config.BreakerConfig{FailureThreshold: 5, OpenTimeout: time.Minute})
+
+	if _, err := c.doGet(context.Background(), c.baseURL+"/UserStories/1"); err != nil {
+		t.Fatalf("expected the timed out request to be retried, got: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 calls, got: %d", calls.Load())
+	}
+
+	c.retryConfig.MaxRetries = 0
+	calls.Store(0)
+	if _, err := c.doGet(context.Background(), c.baseURL+"/UserStories/1"); err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if s := c.breaker.status(); s.ConsecutiveFailures != 1 {
+		t.Errorf("expected the timeout to count as a failure, got %+v", s)
+	}
+}
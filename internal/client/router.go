@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
+)
+
+type contextKey struct{}
+
+type instanceKey struct{}
+
+// WithClient returns a context that routes calls made through a context
+// router (see NewContextRouter) for the default instance to c
+func WithClient(ctx context.Context, c Client) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Client carried by ctx, if any
+func FromContext(ctx context.Context) (Client, bool) {
+	c, ok := ctx.Value(contextKey{}).(Client)
+	return c, ok
+}
+
+// WithInstance returns a context that routes calls made through a context
+// router to the named instance
+func WithInstance(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, instanceKey{}, name)
+}
+
+// InstanceFromContext returns the instance name carried by ctx, if any
+func InstanceFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(instanceKey{}).(string)
+	return name, ok && name != ""
+}
+
+// Instance is a named Target Process instance served by a context router
+type Instance struct {
+	Name   string
+	Domain string
+	Client Client
+}
+
+// InstanceInfo describes an instance without exposing its client or credentials
+type InstanceInfo struct {
+	Name    string `json:"name"`
+	Domain  string `json:"domain"`
+	Default bool   `json:"default"`
+}
+
+// InstanceLister is implemented by clients that serve more than one instance
+type InstanceLister interface {
+	Instances() []InstanceInfo
+}
+
+// contextRouter dispatches each call to the Client selected by its context
+type contextRouter struct {
+	def    Instance
+	others []Instance
+}
+
+// NewContextRouter creates a Client that forwards every call to the instance
+// named in the call's context with WithInstance, falling back to def. Calls
+// for def go to the Client attached with WithClient when there is one, which
+// lets HTTP sessions act with their own credentials while tools keep a single
+// injected Client. Such a session's credentials are for def only, so its
+// calls for other instances are refused rather than made with the shared
+// credentials of those instances.
+func NewContextRouter(def Instance, others ...Instance) Client {
+	return &contextRouter{def: def, others: others}
+}
+
+func (r *contextRouter) resolve(ctx context.Context) (Client, error) {
+	name, ok := InstanceFromContext(ctx)
+	session, hasSession := FromContext(ctx)
+	if !ok || name == r.def.Name {
+		if hasSession {
+			return session, nil
+		}
+		return r.def.Client, nil
+	}
+	for _, inst := range r.others {
+		if inst.Name == name {
+			if hasSession {
+				return nil, fmt.Errorf("instance %q is not available to sessions using their own access token; only the default instance %q is", name, r.def.Name)
+			}
+			return inst.Client, nil
+		}
+	}
+
+	names := []string{r.def.Name}
+	for _, inst := range r.others {
+		names = append(names, inst.Name)
+	}
+	return nil, fmt.Errorf("unknown Target Process instance %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Instances lists the default instance followed by the others
+func (r *contextRouter) Instances() []InstanceInfo {
+	infos := []InstanceInfo{{Name: r.def.Name, Domain: r.def.Domain, Default: true}}
+	for _, inst := range r.others {
+		infos = append(infos, InstanceInfo{Name: inst.Name, Domain: inst.Domain})
+	}
+	return infos
+}
+
+func (r *contextRouter) SearchEntities(ctx context.Context, req query.SearchRequest) (*query.PaginatedResponse, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchEntities(ctx, req)
+}
+
+func (r *contextRouter) QueryV2(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.QueryV2(ctx, req)
+}
+
+func (r *contextRouter) GetEntity(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetEntity(ctx, entityType, id, include)
+}
+
+func (r *contextRouter) CreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateEntity(ctx, entityType, data)
+}
+
+func (r *contextRouter) UpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.UpdateEntity(ctx, entityType, id, data)
+}
+
+func (r *contextRouter) PreviewCreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (*WriteRequest, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.PreviewCreateEntity(ctx, entityType, data)
+}
+
+func (r *contextRouter) PreviewUpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (*WriteRequest, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.PreviewUpdateEntity(ctx, entityType, id, data)
+}
+
+func (r *contextRouter) DeleteEntity(ctx context.Context, entityType entity.Type, id int) error {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	return c.DeleteEntity(ctx, entityType, id)
+}
+
+func (r *contextRouter) PreviewDeleteEntity(ctx context.Context, entityType entity.Type, id int) (*WriteRequest, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.PreviewDeleteEntity(ctx, entityType, id)
+}
+
+func (r *contextRouter) BulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]BulkResult, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.BulkCreate(ctx, entityType, items)
+}
+
+func (r *contextRouter) BulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]BulkResult, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.BulkUpdate(ctx, entityType, items)
+}
+
+func (r *contextRouter) PreviewBulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) (*WriteRequest, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.PreviewBulkCreate(ctx, entityType, items)
+}
+
+func (r *contextRouter) PreviewBulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) (*WriteRequest, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.PreviewBulkUpdate(ctx, entityType, items)
+}
+
+func (r *contextRouter) PreviewCreateComment(ctx context.Context, entityID int, description string) (*WriteRequest, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.PreviewCreateComment(ctx, entityID, description)
+}
+
+func (r *contextRouter) CreateComment(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.CreateComment(ctx, entityID, description)
+}
+
+func (r *contextRouter) ListComments(ctx context.Context, entityID int, take int, include []string) ([]entity.Comment, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListComments(ctx, entityID, take, include)
+}
+
+func (r *contextRouter) ListAttachments(ctx context.Context, entityID int, take int) ([]entity.Attachment, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListAttachments(ctx, entityID, take)
+}
+
+func (r *contextRouter) GetAttachmentMetadata(ctx context.Context, attachmentID int) (*entity.Attachment, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetAttachmentMetadata(ctx, attachmentID)
+}
+
+func (r *contextRouter) DownloadAttachment(ctx context.Context, uri string) ([]byte, string, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.DownloadAttachment(ctx, uri)
+}
+
+func (r *contextRouter) FetchMetadata(ctx context.Context) (any, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.FetchMetadata(ctx)
+}
+
+func (r *contextRouter) GetValidEntityTypes(ctx context.Context) ([]string, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetValidEntityTypes(ctx)
+}
+
+func (r *contextRouter) GetCustomFields(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCustomFields(ctx, entityType)
+}
+
+func (r *contextRouter) GetEntityStates(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error) {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetEntityStates(ctx, entityType, processID)
+}
+
+// InitializeCache warms the cache of every instance; ctx selects the client
+// for the default instance as usual
+func (r *contextRouter) InitializeCache(ctx context.Context) error {
+	c, err := r.resolve(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.InitializeCache(ctx); err != nil {
+		return err
+	}
+	for _, inst := range r.others {
+		if err := inst.Client.InitializeCache(ctx); err != nil {
+			return fmt.Errorf("instance %s: %w", inst.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package client_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/testutil"
+)
+
+func namedClient(name string) *testutil.MockClient {
+	return &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{"Client": name}, nil
+		},
+	}
+}
+
+func TestContextRouter_UsesFallbackWithoutSessionClient(t *testing.T) {
+	r := client.NewContextRouter(client.Instance{Name: "default", Client: namedClient("shared")})
+
+	got, err := r.GetEntity(context.Background(), entity.TypeUserStory, 1, nil)
+	if err != nil {
+		t.Fatalf("GetEntity returned unexpected error: %v", err)
+	}
+	if got["Client"] != "shared" {
+		t.Errorf("expected call to reach the fallback client, reached %v", got["Client"])
+	}
+}
+
+func TestContextRouter_UsesClientFromContext(t *testing.T) {
+	r := client.NewContextRouter(client.Instance{Name: "default", Client: namedClient("shared")})
+	ctx := client.WithClient(context.Background(), namedClient("alice"))
+
+	got, err := r.GetEntity(ctx, entity.TypeUserStory, 1, nil)
+	if err != nil {
+		t.Fatalf("GetEntity returned unexpected error: %v", err)
+	}
+	if got["Client"] != "alice" {
+		t.Errorf("expected call to reach the session client, reached %v", got["Client"])
+	}
+}
+
+func newInstanceRouter() client.Client {
+	return client.NewContextRouter(
+		client.Instance{Name: "prod", Domain: "prod.tpondemand.com", Client: namedClient("prod")},
+		client.Instance{Name: "sandbox", Domain: "sandbox.tpondemand.com", Client: namedClient("sandbox")},
+	)
+}
+
+func TestContextRouter_UsesNamedInstance(t *testing.T) {
+	r := newInstanceRouter()
+
+	tests := []struct {
+		instance string
+		want     string
+	}{
+		{"", "prod"},
+		{"prod", "prod"},
+		{"sandbox", "sandbox"},
+	}
+	for _, tt := range tests {
+		got, err := r.GetEntity(client.WithInstance(context.Background(), tt.instance), entity.TypeUserStory, 1, nil)
+		if err != nil {
+			t.Fatalf("instance %q: unexpected error: %v", tt.instance, err)
+		}
+		if got["Client"] != tt.want {
+			t.Errorf("instance %q: reached %v, want %s", tt.instance, got["Client"], tt.want)
+		}
+	}
+}
+
+func TestContextRouter_SessionClientOnlyServesDefaultInstance(t *testing.T) {
+	r := newInstanceRouter()
+	ctx := client.WithClient(context.Background(), namedClient("alice"))
+
+	for _, instance := range []string{"", "prod"} {
+		got, err := r.GetEntity(client.WithInstance(ctx, instance), entity.TypeUserStory, 1, nil)
+		if err != nil {
+			t.Fatalf("instance %q: unexpected error: %v", instance, err)
+		}
+		if got["Client"] != "alice" {
+			t.Errorf("instance %q: reached %v, want the session client", instance, got["Client"])
+		}
+	}
+
+	// The shared sandbox credentials must not stand in for the session's own
+	got, err := r.GetEntity(client.WithInstance(ctx, "sandbox"), entity.TypeUserStory, 1, nil)
+	if err == nil {
+		t.Fatalf("expected the sandbox instance to be refused, reached %v", got["Client"])
+	}
+	if !strings.Contains(err.Error(), "own access token") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestContextRouter_UnknownInstance(t *testing.T) {
+	r := newInstanceRouter()
+
+	_, err := r.GetEntity(client.WithInstance(context.Background(), "staging"), entity.TypeUserStory, 1, nil)
+	if err == nil {
+		t.Fatal("expected error for unknown instance")
+	}
+	if !strings.Contains(err.Error(), "prod, sandbox") {
+		t.Errorf("expected error to list available instances, got: %v", err)
+	}
+}
+
+func TestContextRouter_Instances(t *testing.T) {
+	lister, ok := newInstanceRouter().(client.InstanceLister)
+	if !ok {
+		t.Fatal("context router does not implement InstanceLister")
+	}
+	got := lister.Instances()
+	if len(got) != 2 || !got[0].Default || got[0].Name != "prod" || got[1].Default || got[1].Domain != "sandbox.tpondemand.com" {
+		t.Errorf("unexpected instances: %+v", got)
+	}
+}
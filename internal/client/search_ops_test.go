@@ -36,7 +36,8 @@ func newTestClient(serverURL string) *httpClient {
 			InitialDelay:  0,
 			BackoffFactor: 1.0,
 		},
-		token: "test-token",
+		token:     "test-token",
+		resources: &resourceNames{},
 	}
 }
 
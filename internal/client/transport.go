@@ -0,0 +1,82 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"tp-mcp-go/internal/config"
+)
+
+// transports holds one *http.Transport per endpoint settings, so every client
+// for an endpoint, including per-session clients, shares its connection pool
+var transports = struct {
+	sync.Mutex
+	m map[config.EndpointConfig]*http.Transport
+}{m: make(map[config.EndpointConfig]*http.Transport)}
+
+// sharedTransport returns the transport for cfg, creating it on first use
+func sharedTransport(cfg config.EndpointConfig) (*http.Transport, error) {
+	transports.Lock()
+	defer transports.Unlock()
+	if t, ok := transports.m[cfg]; ok {
+		return t, nil
+	}
+	t, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	transports.m[cfg] = t
+	return t, nil
+}
+
+// newTransport creates a transport that uses cfg's proxy, CA bundle and
+// client certificate
+func newTransport(cfg config.EndpointConfig) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+	if cfg.CAFile == "" && cfg.ClientCert == "" {
+		return t, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s contains no PEM certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = tlsConfig
+	return t, nil
+}
+
+// apiBaseURL is the root of the v1 API of domain under cfg
+func apiBaseURL(domain string, cfg config.EndpointConfig) string {
+	return fmt.Sprintf("%s://%s%s/api/v1", cfg.Scheme, domain, strings.TrimRight(cfg.BasePath, "/"))
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/client/auth"
+	"tp-mcp-go/internal/config"
+)
+
+func TestAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		domain   string
+		endpoint config.EndpointConfig
+		want     string
+	}{
+		{"company.tpondemand.com", config.EndpointConfig{Scheme: "https"}, "https://company.tpondemand.com/api/v1"},
+		{"tp.corp:8080", config.EndpointConfig{Scheme: "http"}, "http://tp.corp:8080/api/v1"},
+		{"tp.corp", config.EndpointConfig{Scheme: "https", BasePath: "/targetprocess/"}, "https://tp.corp/targetprocess/api/v1"},
+	}
+
+	for _, tt := range tests {
+		if got := apiBaseURL(tt.domain, tt.endpoint); got != tt.want {
+			t.Errorf("apiBaseURL(%q, %+v) = %q, want %q", tt.domain, tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestResolveURL_BasePath(t *testing.T) {
+	c := &httpClient{baseURL: "https://tp.corp/tp/api/v1"}
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"/api/v1/Attachments/1/download", "https://tp.corp/tp/api/v1/Attachments/1/download"},
+		{"/tp/Attachment.aspx?AttachmentID=1", "https://tp.corp/tp/Attachment.aspx?AttachmentID=1"},
+		{"https://tp.corp/tp/Attachment.aspx", "https://tp.corp/tp/Attachment.aspx"},
+	}
+
+	for _, tt := range tests {
+		if got := c.resolveURL(tt.uri); got != tt.want {
+			t.Errorf("resolveURL(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+		if err := validateURL(c.resolveURL(tt.uri), c.baseURL); err != nil {
+			t.Errorf("validateURL rejected %q: %v", tt.uri, err)
+		}
+	}
+}
+
+func TestNewHTTPClient_CustomCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/tp/api/v1/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"Id": 7}`))
+	}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Domain:   strings.TrimPrefix(server.URL, "https://"),
+		Endpoint: config.EndpointConfig{Scheme: "https", BasePath: "/tp", CAFile: caFile},
+		Timeouts: config.TimeoutConfig{HTTP: 5 * time.Second},
+	}
+	c, err := NewHTTPClient(cfg, auth.NewBearerStrategy("t"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hc := c.(*httpClient)
+	if _, err := hc.doGet(context.Background(), hc.baseURL+"/UserStories/7"); err != nil {
+		t.Errorf("expected the custom CA to be trusted, got %v", err)
+	}
+}
+
+func TestNewTransport_Errors(t *testing.T) {
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.EndpointConfig
+	}{
+		{"missing CA file", config.EndpointConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"CA file without certificates", config.EndpointConfig{CAFile: notPEM}},
+		{"missing client certificate", config.EndpointConfig{ClientCert: "missing.crt", ClientKey: "missing.key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newTransport(tt.cfg); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestNewTransport_Proxy(t *testing.T) {
+	transport, err := newTransport(config.EndpointConfig{Proxy: "http://proxy.corp:3128"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, _ := http.NewRequest("GET", "https://tp.corp/api/v1/UserStories", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil || proxyURL == nil || proxyURL.Host != "proxy.corp:3128" {
+		t.Errorf("Proxy() = %v, %v; want proxy.corp:3128", proxyURL, err)
+	}
+}
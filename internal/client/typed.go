@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
+)
+
+// TypedResponse is a page of search results decoded into T
+type TypedResponse[T any] struct {
+	Items      []T                  `json:"items"`
+	Pagination query.PaginationMeta `json:"pagination"`
+}
+
+// GetEntityAs retrieves an entity through c and decodes it into T, such as
+// entity.UserStory for entity.TypeUserStory. It works with any Client, so
+// the cache, audit and routing layers all apply.
+func GetEntityAs[T any](ctx context.Context, c Client, entityType entity.Type, id int, include []string) (*T, error) {
+	raw, err := c.GetEntity(ctx, entityType, id, include)
+	if err != nil {
+		return nil, err
+	}
+	return entity.Decode[T](raw)
+}
+
+// SearchEntitiesAs runs a search through c and decodes every result into T.
+// An item that does not fit T fails the whole page.
+func SearchEntitiesAs[T any](ctx context.Context, c Client, req query.SearchRequest) (*TypedResponse[T], error) {
+	resp, err := c.SearchEntities(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	items, err := entity.DecodeAll[T](resp.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedResponse[T]{Items: items, Pagination: resp.Pagination}, nil
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
+)
+
+func TestGetEntityAs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Id": 5, "Name": "Crash on save", "Severity": {"Id": 2, "Name": "Critical"}}`))
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+
+	bug, err := GetEntityAs[entity.Bug](context.Background(), c, entity.TypeBug, 5, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bug.ID != 5 || bug.Severity == nil || bug.Severity.Name != "Critical" {
+		t.Errorf("unexpected bug: %+v", bug)
+	}
+}
+
+func TestSearchEntitiesAs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Items": [{"Id": 1, "Name": "Sprint 1", "IsCurrent": true}], "Next": "` + "http://" + r.Host + `/api/v1/Iterations?skip=1"}`))
+	}))
+	defer server.Close()
+	c := newTestClient(server.URL)
+
+	resp, err := SearchEntitiesAs[entity.Iteration](context.Background(), c, query.SearchRequest{EntityType: entity.TypeIteration})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Items) != 1 || !resp.Items[0].IsCurrent || !resp.Pagination.HasMore {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// WriteRequest is the request a mutating call sends to the TP API, as
+// returned by the Preview methods for dry runs. URL excludes the access token.
+// Bulk requests send Items as their body instead of Body.
+type WriteRequest struct {
+	Method string           `json:"method"`
+	URL    string           `json:"url"`
+	Body   map[string]any   `json:"body,omitempty"`
+	Items  []map[string]any `json:"items,omitempty"`
+}
+
+// PreviewCreateEntity returns the request CreateEntity would send
+func (c *httpClient) PreviewCreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (*WriteRequest, error) {
+	return &WriteRequest{Method: http.MethodPost, URL: c.buildURL(entityType), Body: data}, nil
+}
+
+// PreviewUpdateEntity returns the request UpdateEntity would send
+func (c *httpClient) PreviewUpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (*WriteRequest, error) {
+	return &WriteRequest{Method: http.MethodPost, URL: fmt.Sprintf("%s/%d", c.buildURL(entityType), id), Body: data}, nil
+}
+
+// PreviewDeleteEntity returns the request DeleteEntity would send
+func (c *httpClient) PreviewDeleteEntity(ctx context.Context, entityType entity.Type, id int) (*WriteRequest, error) {
+	return &WriteRequest{Method: http.MethodDelete, URL: fmt.Sprintf("%s/%d", c.buildURL(entityType), id)}, nil
+}
+
+// PreviewBulkCreate returns the request BulkCreate would send
+func (c *httpClient) PreviewBulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) (*WriteRequest, error) {
+	return &WriteRequest{Method: http.MethodPost, URL: c.buildURL(entityType) + "/bulk", Items: items}, nil
+}
+
+// PreviewBulkUpdate returns the request BulkUpdate would send
+func (c *httpClient) PreviewBulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) (*WriteRequest, error) {
+	return &WriteRequest{Method: http.MethodPost, URL: c.buildURL(entityType) + "/bulk", Items: items}, nil
+}
+
+// PreviewCreateComment returns the request CreateComment would send
+func (c *httpClient) PreviewCreateComment(ctx context.Context, entityID int, description string) (*WriteRequest, error) {
+	return &WriteRequest{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("%s/Comments", c.baseURL),
+		Body: map[string]any{
+			"Description": description,
+			"General":     map[string]any{"Id": entityID},
+			"IsPrivate":   true,
+		},
+	}, nil
+}
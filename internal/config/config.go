@@ -2,35 +2,815 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Transport names accepted by Config.Transport
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+)
+
+// DefaultInstanceName names the instance described by Domain and AccessToken
+// when TP_INSTANCE_NAME is not set
+const DefaultInstanceName = "default"
+
 type Config struct {
-	Domain      string
-	AccessToken string
-	Retry       RetryConfig
+	Domain      string `yaml:"domain"`
+	AccessToken string `yaml:"accessToken"`
+	// AccessTokenFile and AccessTokenCommand read AccessToken from a file or
+	// a command's output instead, re-reading it as it rotates
+	AccessTokenFile    string          `yaml:"accessTokenFile"`
+	AccessTokenCommand string          `yaml:"accessTokenCommand"`
+	Auth               AuthConfig      `yaml:"auth"`
+	Endpoint           EndpointConfig  `yaml:"endpoint"`
+	Retry              RetryConfig     `yaml:"retry"`
+	RateLimit          RateLimitConfig `yaml:"rateLimit"`
+	Breaker            BreakerConfig   `yaml:"circuitBreaker"`
+	Cache              CacheConfig     `yaml:"cache"`
+	Timeouts           TimeoutConfig   `yaml:"timeouts"`
+	Limits             LimitsConfig    `yaml:"limits"`
+	Tools              ToolsConfig     `yaml:"tools"`
+	Audit              AuditConfig     `yaml:"audit"`
+	Writes             WritesConfig    `yaml:"writes"`
+	Transport          string          `yaml:"transport"`
+	HTTP               HTTPConfig      `yaml:"http"`
+
+	// InstanceName names the default instance (Domain and AccessToken), which
+	// serves every tool call that does not select an instance explicitly
+	InstanceName string `yaml:"instanceName"`
+	// Instances are additional named Target Process instances
+	Instances []InstanceConfig `yaml:"instances"`
+
+	// File is the config file the values were read from, if any
+	File string `yaml:"-"`
+}
+
+// InstanceConfig is a named Target Process instance besides the default one.
+// It authenticates with the same Auth.Method as the default instance.
+type InstanceConfig struct {
+	Name               string `yaml:"name"`
+	Domain             string `yaml:"domain"`
+	AccessToken        string `yaml:"accessToken"`
+	AccessTokenFile    string `yaml:"accessTokenFile"`
+	AccessTokenCommand string `yaml:"accessTokenCommand"`
+	// Username and Password are used instead of AccessToken with basic auth
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// Auth methods accepted by AuthConfig.Method
+const (
+	// AuthAccessToken sends AccessToken in the access_token query parameter
+	AuthAccessToken = "access_token"
+	// AuthBearer sends AccessToken in an "Authorization: Bearer" header
+	AuthBearer = "bearer"
+	// AuthBasic sends Username and Password with HTTP Basic auth
+	AuthBasic = "basic"
+)
+
+// AuthConfig decides how requests to Target Process are authenticated
+type AuthConfig struct {
+	Method   string `yaml:"method"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// TokenRefresh is how often a token from AccessTokenFile or
+	// AccessTokenCommand is re-read; zero re-reads it only when TP rejects it
+	TokenRefresh time.Duration `yaml:"tokenRefresh"`
+}
+
+// UsesToken reports whether the method authenticates with AccessToken
+func (a AuthConfig) UsesToken() bool {
+	return a.Method != AuthBasic
+}
+
+// ToolsConfig decides which tools the server registers. Tools that are not
+// registered are neither listed nor callable.
+type ToolsConfig struct {
+	// ReadOnly registers only tools that never modify Target Process data
+	ReadOnly bool `yaml:"readOnly"`
+	// Allow, when non-empty, registers only the named tools
+	Allow []string `yaml:"allow"`
+	// Deny never registers the named tools
+	Deny []string `yaml:"deny"`
+}
+
+// AuditConfig configures the JSONL log of mutating operations
+type AuditConfig struct {
+	// Path of the log file; empty disables auditing
+	Path string `yaml:"path"`
+	// MaxSize is the size in bytes past which the file is rotated
+	MaxSize int64 `yaml:"maxSize"`
+	// MaxBackups is the number of rotated files kept as Path.1, Path.2, ...;
+	// the file just rotated is kept even when it is 0
+	MaxBackups int `yaml:"maxBackups"`
+}
+
+// WritesConfig governs the tools that modify Target Process data
+type WritesConfig struct {
+	// DryRun makes every write tool preview its request instead of sending it
+	DryRun bool `yaml:"dryRun"`
+}
+
+// DefaultSessionTokenHeader is the request header that carries a session's own TP access token
+const DefaultSessionTokenHeader = "X-TP-Access-Token"
+
+// HTTPConfig configures the Streamable HTTP / SSE listener used when Transport is "http"
+type HTTPConfig struct {
+	ListenAddr  string `yaml:"listenAddr"`
+	BearerToken string `yaml:"bearerToken"`
+
+	// SessionTokenHeader names the header a client may send when opening a
+	// session to act with its own TP access token instead of AccessToken
+	SessionTokenHeader string `yaml:"sessionTokenHeader"`
+	// RequireSessionToken rejects sessions that do not send SessionTokenHeader
+	RequireSessionToken bool `yaml:"requireSessionToken"`
+	// SessionIdleTimeout ends Streamable HTTP sessions no request has used
+	// for that long; zero keeps them until the client deletes them
+	SessionIdleTimeout time.Duration `yaml:"sessionIdleTimeout"`
+}
+
+// EndpointConfig says how the TP API of every instance is reached, for
+// self-hosted installs. A non-standard port goes in the domain.
+type EndpointConfig struct {
+	// Scheme is "https" (the default) or "http"
+	Scheme string `yaml:"scheme"`
+	// BasePath is the path TP is served under, such as /tp; empty for the root
+	BasePath string `yaml:"basePath"`
+	// Proxy is the URL of the proxy to use; empty falls back to the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+	Proxy string `yaml:"proxy"`
+	// CAFile is a PEM bundle trusted in addition to the system roots
+	CAFile string `yaml:"caFile"`
+	// ClientCert and ClientKey are PEM files presented for mutual TLS
+	ClientCert string `yaml:"clientCert"`
+	ClientKey  string `yaml:"clientKey"`
 }
 
 type RetryConfig struct {
-	MaxRetries    int
-	InitialDelay  time.Duration
-	BackoffFactor float64
+	MaxRetries    int           `yaml:"maxRetries"`
+	InitialDelay  time.Duration `yaml:"initialDelay"`
+	BackoffFactor float64       `yaml:"backoffFactor"`
+	// Jitter randomizes each delay by up to this fraction in either direction
+	// so that clients failing together do not retry together
+	Jitter float64 `yaml:"jitter"`
+	// MaxDelay caps the wait between attempts, including waits asked for by a
+	// Retry-After header
+	MaxDelay time.Duration `yaml:"maxDelay"`
 }
 
-func Load() (*Config, error) {
-	domain := os.Getenv("TP_DOMAIN")
-	token := os.Getenv("TP_ACCESS_TOKEN")
-	if domain == "" || token == "" {
-		return nil, fmt.Errorf("TP_DOMAIN and TP_ACCESS_TOKEN environment variables are required")
-	}
+// RateLimitConfig is the client-side budget for requests to one TP instance
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate; zero disables the limit
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	// Burst is how many requests may be sent at once after a quiet period
+	Burst int `yaml:"burst"`
+}
+
+// BreakerConfig controls the circuit breaker in front of each TP instance
+type BreakerConfig struct {
+	// FailureThreshold is how many consecutive failed requests open the
+	// breaker; zero disables it
+	FailureThreshold int `yaml:"failureThreshold"`
+	// OpenTimeout is how long the breaker fails requests fast before letting
+	// a single probe request through
+	OpenTimeout time.Duration `yaml:"openTimeout"`
+}
+
+// CacheConfig controls the in-memory cache of GET responses from the TP API.
+// Writes made through the server evict what they change; changes made in TP
+// directly show up once the cached response expires.
+type CacheConfig struct {
+	// Enabled turns the cache on; it is off by default
+	Enabled bool `yaml:"enabled"`
+	// MaxEntries is how many responses each instance keeps before evicting
+	// the least recently used
+	MaxEntries int `yaml:"maxEntries"`
+	// EntityTTL is how long get_entity responses are served from the cache
+	EntityTTL time.Duration `yaml:"entityTTL"`
+	// ListTTL is how long comment and attachment lists are served from the cache
+	ListTTL time.Duration `yaml:"listTTL"`
+	// MetadataTTL is how long API metadata is served from the cache
+	MetadataTTL time.Duration `yaml:"metadataTTL"`
+}
+
+// TimeoutConfig bounds how long calls to the TP API may take
+type TimeoutConfig struct {
+	// HTTP is the timeout of a single TP API request, including attachment downloads
+	HTTP time.Duration `yaml:"http"`
+	// Tool bounds a whole tool call, including retries and the waits between
+	// them; zero disables the deadline
+	Tool time.Duration `yaml:"tool"`
+}
+
+// Defaults returns the configuration used before any file, environment
+// variable or option is applied
+func Defaults() *Config {
 	return &Config{
-		Domain:      domain,
-		AccessToken: token,
+		Auth:     AuthConfig{Method: AuthAccessToken, TokenRefresh: 5 * time.Minute},
+		Endpoint: EndpointConfig{Scheme: "https"},
 		Retry: RetryConfig{
 			MaxRetries:    3,
 			InitialDelay:  1 * time.Second,
 			BackoffFactor: 2.0,
+			Jitter:        0.2,
+			MaxDelay:      30 * time.Second,
 		},
-	}, nil
+		RateLimit: RateLimitConfig{RequestsPerSecond: 10, Burst: 10},
+		Breaker:   BreakerConfig{FailureThreshold: 5, OpenTimeout: 30 * time.Second},
+		Cache: CacheConfig{
+			MaxEntries:  1000,
+			EntityTTL:   time.Minute,
+			ListTTL:     30 * time.Second,
+			MetadataTTL: time.Hour,
+		},
+		Timeouts:  TimeoutConfig{HTTP: 30 * time.Second, Tool: 2 * time.Minute},
+		Audit:     AuditConfig{MaxSize: 10 * 1024 * 1024, MaxBackups: 5},
+		Limits:    DefaultLimits(),
+		Transport: TransportStdio,
+		HTTP: HTTPConfig{
+			ListenAddr:         "127.0.0.1:8080",
+			SessionTokenHeader: DefaultSessionTokenHeader,
+			SessionIdleTimeout: 30 * time.Minute,
+		},
+		InstanceName: DefaultInstanceName,
+	}
+}
+
+// Option overrides a configuration value after environment variables are read
+type Option func(*Config)
+
+// WithDomain overrides TP_DOMAIN
+func WithDomain(domain string) Option {
+	return func(c *Config) {
+		c.Domain = domain
+	}
+}
+
+// WithAccessToken overrides TP_ACCESS_TOKEN
+func WithAccessToken(token string) Option {
+	return func(c *Config) {
+		c.AccessToken = token
+	}
+}
+
+// WithAuthMethod overrides TP_AUTH_METHOD
+func WithAuthMethod(method string) Option {
+	return func(c *Config) {
+		c.Auth.Method = method
+	}
+}
+
+// WithMaxRetries overrides the number of retries after the first attempt
+func WithMaxRetries(n int) Option {
+	return func(c *Config) {
+		c.Retry.MaxRetries = n
+	}
+}
+
+// WithInitialDelay overrides the delay before the first retry
+func WithInitialDelay(d time.Duration) Option {
+	return func(c *Config) {
+		c.Retry.InitialDelay = d
+	}
+}
+
+// WithBackoffFactor overrides the multiplier applied to the delay between retries
+func WithBackoffFactor(f float64) Option {
+	return func(c *Config) {
+		c.Retry.BackoffFactor = f
+	}
+}
+
+// WithRateLimit overrides TP_RATE_LIMIT, the requests per second sent to each instance
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(c *Config) {
+		c.RateLimit.RequestsPerSecond = requestsPerSecond
+	}
+}
+
+// WithTransport overrides TP_MCP_TRANSPORT
+func WithTransport(transport string) Option {
+	return func(c *Config) {
+		c.Transport = transport
+	}
+}
+
+// WithListenAddr overrides TP_MCP_LISTEN_ADDR
+func WithListenAddr(addr string) Option {
+	return func(c *Config) {
+		c.HTTP.ListenAddr = addr
+	}
+}
+
+// WithBearerToken overrides TP_MCP_BEARER_TOKEN
+func WithBearerToken(token string) Option {
+	return func(c *Config) {
+		c.HTTP.BearerToken = token
+	}
+}
+
+// WithSessionTokenHeader overrides TP_MCP_SESSION_TOKEN_HEADER
+func WithSessionTokenHeader(header string) Option {
+	return func(c *Config) {
+		c.HTTP.SessionTokenHeader = header
+	}
+}
+
+// WithRequireSessionToken overrides TP_MCP_REQUIRE_SESSION_TOKEN
+func WithRequireSessionToken(require bool) Option {
+	return func(c *Config) {
+		c.HTTP.RequireSessionToken = require
+	}
+}
+
+// WithReadOnly overrides TP_READ_ONLY
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Config) {
+		c.Tools.ReadOnly = readOnly
+	}
+}
+
+// WithAllowedTools overrides TP_TOOLS_ALLOW
+func WithAllowedTools(names []string) Option {
+	return func(c *Config) {
+		c.Tools.Allow = names
+	}
+}
+
+// WithDeniedTools overrides TP_TOOLS_DENY
+func WithDeniedTools(names []string) Option {
+	return func(c *Config) {
+		c.Tools.Deny = names
+	}
+}
+
+// WithAuditLog overrides TP_AUDIT_LOG
+func WithAuditLog(path string) Option {
+	return func(c *Config) {
+		c.Audit.Path = path
+	}
+}
+
+// WithDryRun overrides TP_DRY_RUN
+func WithDryRun(dryRun bool) Option {
+	return func(c *Config) {
+		c.Writes.DryRun = dryRun
+	}
+}
+
+// WithCache overrides TP_CACHE
+func WithCache(enabled bool) Option {
+	return func(c *Config) {
+		c.Cache.Enabled = enabled
+	}
+}
+
+// WithInstanceName overrides TP_INSTANCE_NAME
+func WithInstanceName(name string) Option {
+	return func(c *Config) {
+		c.InstanceName = name
+	}
+}
+
+// WithInstance adds a named instance, replacing any instance of the same name
+func WithInstance(name, domain, accessToken string) Option {
+	return func(c *Config) {
+		c.addInstance(InstanceConfig{Name: name, Domain: domain, AccessToken: accessToken})
+	}
+}
+
+// addInstance adds inst, replacing any instance of the same name
+func (c *Config) addInstance(inst InstanceConfig) {
+	for i := range c.Instances {
+		if c.Instances[i].Name == inst.Name {
+			c.Instances[i] = inst
+			return
+		}
+	}
+	c.Instances = append(c.Instances, inst)
+}
+
+// Load reads configuration like LoadFile, taking the config file path from
+// TP_CONFIG
+func Load(opts ...Option) (*Config, error) {
+	return LoadFile(os.Getenv("TP_CONFIG"), opts...)
+}
+
+// LoadFile builds the configuration from defaults, the YAML or JSON file at
+// path (skipped when path is empty), environment variables and opts, each
+// overriding the one before, and validates the result
+func LoadFile(path string, opts ...Option) (*Config, error) {
+	cfg := Defaults()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		if cfg.File != "" {
+			return nil, fmt.Errorf("%w (config file %s)", err, cfg.File)
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides c with every environment variable that is set
+func (c *Config) applyEnv() error {
+	envString(&c.Domain, "TP_DOMAIN")
+	envString(&c.AccessToken, "TP_ACCESS_TOKEN")
+	envString(&c.AccessTokenFile, "TP_ACCESS_TOKEN_FILE")
+	envString(&c.AccessTokenCommand, "TP_ACCESS_TOKEN_COMMAND")
+	envString(&c.Auth.Method, "TP_AUTH_METHOD")
+	envString(&c.Auth.Username, "TP_USERNAME")
+	envString(&c.Auth.Password, "TP_PASSWORD")
+	envString(&c.Endpoint.Scheme, "TP_SCHEME")
+	envString(&c.Endpoint.BasePath, "TP_BASE_PATH")
+	envString(&c.Endpoint.Proxy, "TP_PROXY")
+	envString(&c.Endpoint.CAFile, "TP_CA_FILE")
+	envString(&c.Endpoint.ClientCert, "TP_CLIENT_CERT")
+	envString(&c.Endpoint.ClientKey, "TP_CLIENT_KEY")
+	envString(&c.Transport, "TP_MCP_TRANSPORT")
+	envString(&c.HTTP.ListenAddr, "TP_MCP_LISTEN_ADDR")
+	envString(&c.HTTP.BearerToken, "TP_MCP_BEARER_TOKEN")
+	envString(&c.HTTP.SessionTokenHeader, "TP_MCP_SESSION_TOKEN_HEADER")
+	envString(&c.InstanceName, "TP_INSTANCE_NAME")
+	envString(&c.Audit.Path, "TP_AUDIT_LOG")
+	envList(&c.Tools.Allow, "TP_TOOLS_ALLOW")
+	envList(&c.Tools.Deny, "TP_TOOLS_DENY")
+	for _, inst := range instancesFromEnv() {
+		c.addInstance(inst)
+	}
+
+	for _, err := range []error{
+		envBool(&c.HTTP.RequireSessionToken, "TP_MCP_REQUIRE_SESSION_TOKEN"),
+		envBool(&c.Tools.ReadOnly, "TP_READ_ONLY"),
+		envBool(&c.Writes.DryRun, "TP_DRY_RUN"),
+		envBool(&c.Cache.Enabled, "TP_CACHE"),
+		envInt(&c.Cache.MaxEntries, "TP_CACHE_MAX_ENTRIES"),
+		envDuration(&c.Cache.EntityTTL, "TP_CACHE_ENTITY_TTL"),
+		envDuration(&c.Cache.ListTTL, "TP_CACHE_LIST_TTL"),
+		envDuration(&c.Cache.MetadataTTL, "TP_CACHE_METADATA_TTL"),
+		envInt(&c.Retry.MaxRetries, "TP_MAX_RETRIES"),
+		envDuration(&c.Retry.InitialDelay, "TP_RETRY_DELAY"),
+		envFloat(&c.Retry.BackoffFactor, "TP_BACKOFF_FACTOR"),
+		envFloat(&c.Retry.Jitter, "TP_RETRY_JITTER"),
+		envDuration(&c.Retry.MaxDelay, "TP_RETRY_MAX_DELAY"),
+		envFloat(&c.RateLimit.RequestsPerSecond, "TP_RATE_LIMIT"),
+		envInt(&c.RateLimit.Burst, "TP_RATE_BURST"),
+		envInt(&c.Breaker.FailureThreshold, "TP_BREAKER_THRESHOLD"),
+		envDuration(&c.Breaker.OpenTimeout, "TP_BREAKER_OPEN_TIMEOUT"),
+		envDuration(&c.Auth.TokenRefresh, "TP_ACCESS_TOKEN_REFRESH"),
+		envDuration(&c.Timeouts.HTTP, "TP_HTTP_TIMEOUT"),
+		envDuration(&c.Timeouts.Tool, "TP_TOOL_TIMEOUT"),
+		envDuration(&c.HTTP.SessionIdleTimeout, "TP_MCP_SESSION_IDLE_TIMEOUT"),
+		envInt64(&c.Limits.MaxAttachmentSize, "TP_MAX_ATTACHMENT_SIZE"),
+		envInt(&c.Limits.MaxBatchSize, "TP_MAX_BATCH_SIZE"),
+		envInt64(&c.Audit.MaxSize, "TP_AUDIT_MAX_SIZE"),
+		envInt(&c.Audit.MaxBackups, "TP_AUDIT_MAX_BACKUPS"),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks that the configuration is usable
+func (c *Config) Validate() error {
+	// A shared token is optional when every HTTP session must bring its own
+	perSessionOnly := c.Transport == TransportHTTP && c.HTTP.RequireSessionToken
+	switch c.Auth.Method {
+	case AuthAccessToken, AuthBearer:
+		if c.Domain == "" || (!c.HasCredentials() && !perSessionOnly) {
+			return fmt.Errorf("TP_DOMAIN and TP_ACCESS_TOKEN environment variables are required")
+		}
+		if c.AccessTokenFile != "" && c.AccessTokenCommand != "" {
+			return fmt.Errorf("set only one of TP_ACCESS_TOKEN_FILE and TP_ACCESS_TOKEN_COMMAND")
+		}
+	case AuthBasic:
+		if c.Domain == "" || (!c.HasCredentials() && !perSessionOnly) {
+			return fmt.Errorf("TP_DOMAIN, TP_USERNAME and TP_PASSWORD environment variables are required for basic auth")
+		}
+	default:
+		return fmt.Errorf("unknown auth method %q (expected %q, %q or %q)", c.Auth.Method, AuthAccessToken, AuthBearer, AuthBasic)
+	}
+	if c.Auth.TokenRefresh < 0 {
+		return fmt.Errorf("auth.tokenRefresh must not be negative, got %s", c.Auth.TokenRefresh)
+	}
+	if err := c.Endpoint.validate(); err != nil {
+		return err
+	}
+	if c.Timeouts.HTTP <= 0 {
+		return fmt.Errorf("timeouts.http must be positive, got %s", c.Timeouts.HTTP)
+	}
+	if c.Timeouts.Tool < 0 {
+		return fmt.Errorf("timeouts.tool must not be negative, got %s", c.Timeouts.Tool)
+	}
+	if c.HTTP.SessionIdleTimeout < 0 {
+		return fmt.Errorf("http.sessionIdleTimeout must not be negative, got %s", c.HTTP.SessionIdleTimeout)
+	}
+	if err := c.Limits.validate(); err != nil {
+		return err
+	}
+	if c.Audit.Path != "" && c.Audit.MaxSize <= 0 {
+		return fmt.Errorf("audit.maxSize must be positive, got %d", c.Audit.MaxSize)
+	}
+	if c.Audit.MaxBackups < 0 {
+		return fmt.Errorf("audit.maxBackups must not be negative, got %d", c.Audit.MaxBackups)
+	}
+	if err := c.validateInstances(); err != nil {
+		return err
+	}
+	if c.Retry.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative, got %d", c.Retry.MaxRetries)
+	}
+	if c.Retry.InitialDelay < 0 {
+		return fmt.Errorf("initial retry delay must not be negative, got %s", c.Retry.InitialDelay)
+	}
+	if c.Retry.BackoffFactor < 1 {
+		return fmt.Errorf("backoff factor must be at least 1, got %g", c.Retry.BackoffFactor)
+	}
+	if c.Retry.Jitter < 0 || c.Retry.Jitter > 1 {
+		return fmt.Errorf("retry.jitter must be between 0 and 1, got %g", c.Retry.Jitter)
+	}
+	if c.Retry.MaxDelay <= 0 {
+		return fmt.Errorf("retry.maxDelay must be positive, got %s", c.Retry.MaxDelay)
+	}
+	if c.RateLimit.RequestsPerSecond < 0 {
+		return fmt.Errorf("rateLimit.requestsPerSecond must not be negative, got %g", c.RateLimit.RequestsPerSecond)
+	}
+	if c.RateLimit.RequestsPerSecond > 0 && c.RateLimit.Burst < 1 {
+		return fmt.Errorf("rateLimit.burst must be at least 1, got %d", c.RateLimit.Burst)
+	}
+	if c.Breaker.FailureThreshold < 0 {
+		return fmt.Errorf("circuitBreaker.failureThreshold must not be negative, got %d", c.Breaker.FailureThreshold)
+	}
+	if c.Breaker.FailureThreshold > 0 && c.Breaker.OpenTimeout <= 0 {
+		return fmt.Errorf("circuitBreaker.openTimeout must be positive, got %s", c.Breaker.OpenTimeout)
+	}
+	if err := c.Cache.validate(); err != nil {
+		return err
+	}
+	switch c.Transport {
+	case TransportStdio:
+	case TransportHTTP:
+		host, _, err := net.SplitHostPort(c.HTTP.ListenAddr)
+		if err != nil {
+			return fmt.Errorf("invalid listen address %q: %v", c.HTTP.ListenAddr, err)
+		}
+		if c.HTTP.BearerToken == "" && !isLoopback(host) {
+			return fmt.Errorf("TP_MCP_BEARER_TOKEN is required when listening on non-loopback address %q", c.HTTP.ListenAddr)
+		}
+		if c.HTTP.SessionTokenHeader == "" {
+			return fmt.Errorf("session token header must not be empty")
+		}
+	default:
+		return fmt.Errorf("unknown transport %q (expected %q or %q)", c.Transport, TransportStdio, TransportHTTP)
+	}
+	return nil
+}
+
+// validate checks the cache settings; a zero TTL leaves that resource uncached
+func (c CacheConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxEntries < 1 {
+		return fmt.Errorf("cache.maxEntries must be at least 1, got %d", c.MaxEntries)
+	}
+	for _, ttl := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"cache.entityTTL", c.EntityTTL},
+		{"cache.listTTL", c.ListTTL},
+		{"cache.metadataTTL", c.MetadataTTL},
+	} {
+		if ttl.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", ttl.name, ttl.value)
+		}
+	}
+	return nil
+}
+
+// validate checks the endpoint settings; the files they name are read when
+// the client is created
+func (e EndpointConfig) validate() error {
+	if e.Scheme != "https" && e.Scheme != "http" {
+		return fmt.Errorf("endpoint.scheme must be https or http, got %q", e.Scheme)
+	}
+	if e.BasePath != "" && !strings.HasPrefix(e.BasePath, "/") {
+		return fmt.Errorf("endpoint.basePath must start with /, got %q", e.BasePath)
+	}
+	if e.Proxy != "" {
+		u, err := url.Parse(e.Proxy)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			return fmt.Errorf("endpoint.proxy must be an http, https or socks5 URL, got %q", e.Proxy)
+		}
+	}
+	if (e.ClientCert == "") != (e.ClientKey == "") {
+		return fmt.Errorf("endpoint.clientCert and endpoint.clientKey must be set together")
+	}
+	return nil
+}
+
+// HasCredentials reports whether the default instance has credentials of its
+// own, rather than relying on per-session tokens
+func (c *Config) HasCredentials() bool {
+	if !c.Auth.UsesToken() {
+		return c.Auth.Username != "" && c.Auth.Password != ""
+	}
+	return c.AccessToken != "" || c.AccessTokenFile != "" || c.AccessTokenCommand != ""
+}
+
+// ForInstance returns a copy of c that connects to inst instead of the default instance
+func (c *Config) ForInstance(inst InstanceConfig) *Config {
+	out := *c
+	out.Domain = inst.Domain
+	out.AccessToken = inst.AccessToken
+	out.AccessTokenFile = inst.AccessTokenFile
+	out.AccessTokenCommand = inst.AccessTokenCommand
+	out.Auth.Username = inst.Username
+	out.Auth.Password = inst.Password
+	return &out
+}
+
+// validateInstances checks instance names are usable and unique and that
+// every additional instance is complete
+func (c *Config) validateInstances() error {
+	if !validInstanceName(c.InstanceName) {
+		return fmt.Errorf("invalid instance name %q: use lowercase letters, digits, '-' and '_'", c.InstanceName)
+	}
+	seen := map[string]bool{c.InstanceName: true}
+	for _, inst := range c.Instances {
+		if !validInstanceName(inst.Name) {
+			return fmt.Errorf("invalid instance name %q: use lowercase letters, digits, '-' and '_'", inst.Name)
+		}
+		if seen[inst.Name] {
+			return fmt.Errorf("duplicate instance name %q", inst.Name)
+		}
+		seen[inst.Name] = true
+		if !c.ForInstance(inst).HasCredentials() || inst.Domain == "" {
+			if !c.Auth.UsesToken() {
+				return fmt.Errorf("instance %q requires a domain, a username and a password for basic auth", inst.Name)
+			}
+			return fmt.Errorf("instance %q requires both a domain and an access token", inst.Name)
+		}
+		if inst.AccessTokenFile != "" && inst.AccessTokenCommand != "" {
+			return fmt.Errorf("instance %q sets both an access token file and command", inst.Name)
+		}
+	}
+	return nil
+}
+
+// validInstanceName reports whether name can be used as an instance name
+func validInstanceName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// instancesFromEnv reads the instances listed in TP_INSTANCES. Each name has
+// its domain and token in TP_INSTANCE_<NAME>_DOMAIN and
+// TP_INSTANCE_<NAME>_ACCESS_TOKEN (or _ACCESS_TOKEN_FILE or
+// _ACCESS_TOKEN_COMMAND), or its basic auth credentials in
+// TP_INSTANCE_<NAME>_USERNAME and _PASSWORD, with '-' in the name written as '_'.
+func instancesFromEnv() []InstanceConfig {
+	var instances []InstanceConfig
+	for _, name := range SplitList(os.Getenv("TP_INSTANCES")) {
+		prefix := "TP_INSTANCE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		instances = append(instances, InstanceConfig{
+			Name:               name,
+			Domain:             os.Getenv(prefix + "DOMAIN"),
+			AccessToken:        os.Getenv(prefix + "ACCESS_TOKEN"),
+			AccessTokenFile:    os.Getenv(prefix + "ACCESS_TOKEN_FILE"),
+			AccessTokenCommand: os.Getenv(prefix + "ACCESS_TOKEN_COMMAND"),
+			Username:           os.Getenv(prefix + "USERNAME"),
+			Password:           os.Getenv(prefix + "PASSWORD"),
+		})
+	}
+	return instances
+}
+
+// envString sets *dst to the environment variable when it is set and non-empty
+func envString(dst *string, key string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+// envList sets *dst to the comma-separated environment variable when it is set
+func envList(dst *[]string, key string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = SplitList(v)
+	}
+}
+
+// SplitList splits a comma-separated list, dropping blanks around and between items
+func SplitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envBool parses a boolean environment variable into *dst when it is set
+func envBool(dst *bool, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: expected true or false", key, v)
+	}
+	*dst = b
+	return nil
+}
+
+// envInt parses an integer environment variable into *dst when it is set
+func envInt(dst *int, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: expected an integer", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+// envInt64 parses an integer environment variable into *dst when it is set
+func envInt64(dst *int64, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: expected an integer", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+// envFloat parses a numeric environment variable into *dst when it is set
+func envFloat(dst *float64, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: expected a number", key, v)
+	}
+	*dst = f
+	return nil
+}
+
+// envDuration parses a duration environment variable (e.g. "1500ms") into *dst when it is set
+func envDuration(dst *time.Duration, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: expected a duration such as 1s or 500ms", key, v)
+	}
+	*dst = d
+	return nil
+}
+
+// isLoopback reports whether host only accepts local connections
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
 }
@@ -84,3 +84,552 @@ func TestLoad_DefaultRetryValues(t *testing.T) {
 		t.Errorf("Retry.BackoffFactor = %f, want 2.0", cfg.Retry.BackoffFactor)
 	}
 }
+
+func TestLoad_OptionsOverrideEnv(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "env.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "env-token")
+
+	cfg, err := Load(
+		WithDomain("flag.tpondemand.com"),
+		WithMaxRetries(5),
+		WithInitialDelay(250*time.Millisecond),
+		WithBackoffFactor(1.5),
+	)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.Domain != "flag.tpondemand.com" {
+		t.Errorf("Domain = %q, want %q", cfg.Domain, "flag.tpondemand.com")
+	}
+	if cfg.AccessToken != "env-token" {
+		t.Errorf("AccessToken = %q, want %q", cfg.AccessToken, "env-token")
+	}
+	if cfg.Retry.MaxRetries != 5 {
+		t.Errorf("Retry.MaxRetries = %d, want 5", cfg.Retry.MaxRetries)
+	}
+	if cfg.Retry.InitialDelay != 250*time.Millisecond {
+		t.Errorf("Retry.InitialDelay = %v, want 250ms", cfg.Retry.InitialDelay)
+	}
+	if cfg.Retry.BackoffFactor != 1.5 {
+		t.Errorf("Retry.BackoffFactor = %f, want 1.5", cfg.Retry.BackoffFactor)
+	}
+}
+
+func TestLoad_OptionsSatisfyRequired(t *testing.T) {
+	cfg, err := Load(WithDomain("flag.tpondemand.com"), WithAccessToken("flag-token"))
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.AccessToken != "flag-token" {
+		t.Errorf("AccessToken = %q, want %q", cfg.AccessToken, "flag-token")
+	}
+}
+
+func TestLoad_InvalidRetryValues(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"negative retries", WithMaxRetries(-1)},
+		{"negative delay", WithInitialDelay(-time.Second)},
+		{"backoff below one", WithBackoffFactor(0.5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load(tt.opt)
+			if err == nil {
+				t.Fatal("Load() expected error, got nil")
+			}
+			if cfg != nil {
+				t.Errorf("Load() expected nil config, got %v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoad_DefaultTransport(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Transport != TransportStdio {
+		t.Errorf("Transport = %q, want %q", cfg.Transport, TransportStdio)
+	}
+	if cfg.HTTP.ListenAddr != "127.0.0.1:8080" {
+		t.Errorf("HTTP.ListenAddr = %q, want %q", cfg.HTTP.ListenAddr, "127.0.0.1:8080")
+	}
+}
+
+func TestLoad_HTTPTransport(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name: "loopback without bearer token",
+			env:  map[string]string{"TP_MCP_TRANSPORT": "http"},
+		},
+		{
+			name:    "public address without bearer token",
+			env:     map[string]string{"TP_MCP_TRANSPORT": "http", "TP_MCP_LISTEN_ADDR": "0.0.0.0:8080"},
+			wantErr: true,
+		},
+		{
+			name: "public address with bearer token",
+			env: map[string]string{
+				"TP_MCP_TRANSPORT":    "http",
+				"TP_MCP_LISTEN_ADDR":  "0.0.0.0:8080",
+				"TP_MCP_BEARER_TOKEN": "secret",
+			},
+		},
+		{
+			name:    "malformed listen address",
+			opts:    []Option{WithTransport(TransportHTTP), WithListenAddr("8080")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown transport",
+			opts:    []Option{WithTransport("carrier-pigeon")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load(tt.opts...)
+			if tt.wantErr && err == nil {
+				t.Fatal("Load() expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_SessionTokens(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		opts    []Option
+		wantErr bool
+	}{
+		{
+			name: "shared token optional when sessions must bring their own",
+			env:  map[string]string{"TP_MCP_TRANSPORT": "http", "TP_MCP_REQUIRE_SESSION_TOKEN": "true"},
+		},
+		{
+			name:    "shared token required for stdio",
+			opts:    []Option{WithRequireSessionToken(true)},
+			wantErr: true,
+		},
+		{
+			name:    "shared token required when session tokens are optional",
+			env:     map[string]string{"TP_MCP_TRANSPORT": "http"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid boolean",
+			env:     map[string]string{"TP_MCP_TRANSPORT": "http", "TP_MCP_REQUIRE_SESSION_TOKEN": "sometimes"},
+			wantErr: true,
+		},
+		{
+			name:    "empty header",
+			env:     map[string]string{"TP_MCP_TRANSPORT": "http", "TP_ACCESS_TOKEN": "shared"},
+			opts:    []Option{WithSessionTokenHeader("")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load(tt.opts...)
+			if tt.wantErr && err == nil {
+				t.Fatal("Load() expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoad_DefaultSessionTokenHeader(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.HTTP.SessionTokenHeader != DefaultSessionTokenHeader {
+		t.Errorf("HTTP.SessionTokenHeader = %q, want %q", cfg.HTTP.SessionTokenHeader, DefaultSessionTokenHeader)
+	}
+	if cfg.HTTP.RequireSessionToken {
+		t.Error("HTTP.RequireSessionToken = true, want false")
+	}
+}
+
+func TestLoad_Instances(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "prod.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "prod-token")
+	t.Setenv("TP_INSTANCE_NAME", "prod")
+	t.Setenv("TP_INSTANCES", "sandbox, eu-test")
+	t.Setenv("TP_INSTANCE_SANDBOX_DOMAIN", "sandbox.tpondemand.com")
+	t.Setenv("TP_INSTANCE_SANDBOX_ACCESS_TOKEN", "sandbox-token")
+	t.Setenv("TP_INSTANCE_EU_TEST_DOMAIN", "eu.tpondemand.com")
+	t.Setenv("TP_INSTANCE_EU_TEST_ACCESS_TOKEN", "eu-token")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.InstanceName != "prod" {
+		t.Errorf("InstanceName = %q, want %q", cfg.InstanceName, "prod")
+	}
+	want := []InstanceConfig{
+		{Name: "sandbox", Domain: "sandbox.tpondemand.com", AccessToken: "sandbox-token"},
+		{Name: "eu-test", Domain: "eu.tpondemand.com", AccessToken: "eu-token"},
+	}
+	if len(cfg.Instances) != len(want) {
+		t.Fatalf("got %d instances, want %d", len(cfg.Instances), len(want))
+	}
+	for i := range want {
+		if cfg.Instances[i] != want[i] {
+			t.Errorf("Instances[%d] = %+v, want %+v", i, cfg.Instances[i], want[i])
+		}
+	}
+}
+
+func TestLoad_InvalidInstances(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "prod.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "prod-token")
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"missing token", []Option{WithInstance("sandbox", "sandbox.tpondemand.com", "")}},
+		{"duplicate of default", []Option{WithInstance(DefaultInstanceName, "sandbox.tpondemand.com", "t")}},
+		{"bad name", []Option{WithInstance("Sand Box", "sandbox.tpondemand.com", "t")}},
+		{"empty default name", []Option{WithInstanceName("")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.opts...); err == nil {
+				t.Fatal("Load() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoad_ToolPolicy(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+	t.Setenv("TP_READ_ONLY", "true")
+	t.Setenv("TP_TOOLS_ALLOW", "search, get_entity,")
+	t.Setenv("TP_TOOLS_DENY", "get_entity")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if !cfg.Tools.ReadOnly {
+		t.Error("Tools.ReadOnly = false, want true")
+	}
+	if len(cfg.Tools.Allow) != 2 || cfg.Tools.Allow[0] != "search" || cfg.Tools.Allow[1] != "get_entity" {
+		t.Errorf("Tools.Allow = %v, want [search get_entity]", cfg.Tools.Allow)
+	}
+	if len(cfg.Tools.Deny) != 1 || cfg.Tools.Deny[0] != "get_entity" {
+		t.Errorf("Tools.Deny = %v, want [get_entity]", cfg.Tools.Deny)
+	}
+}
+
+func TestLoad_DryRun(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+	t.Setenv("TP_DRY_RUN", "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if !cfg.Writes.DryRun {
+		t.Error("Writes.DryRun = false, want true")
+	}
+
+	cfg, err = Load(WithDryRun(false))
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Writes.DryRun {
+		t.Error("Writes.DryRun = true after WithDryRun(false), want false")
+	}
+}
+
+func TestLoad_RateLimit(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.RateLimit != (RateLimitConfig{RequestsPerSecond: 10, Burst: 10}) {
+		t.Errorf("RateLimit = %+v, want default {10 10}", cfg.RateLimit)
+	}
+
+	t.Setenv("TP_RATE_LIMIT", "2.5")
+	t.Setenv("TP_RATE_BURST", "4")
+	t.Setenv("TP_RETRY_JITTER", "0")
+	t.Setenv("TP_RETRY_MAX_DELAY", "5s")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.RateLimit != (RateLimitConfig{RequestsPerSecond: 2.5, Burst: 4}) {
+		t.Errorf("RateLimit = %+v, want {2.5 4}", cfg.RateLimit)
+	}
+	if cfg.Retry.Jitter != 0 || cfg.Retry.MaxDelay != 5*time.Second {
+		t.Errorf("Retry = %+v, want jitter 0 and max delay 5s", cfg.Retry)
+	}
+
+	cfg, err = Load(WithRateLimit(0))
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.RateLimit.RequestsPerSecond != 0 {
+		t.Errorf("RateLimit.RequestsPerSecond = %g after WithRateLimit(0), want 0", cfg.RateLimit.RequestsPerSecond)
+	}
+}
+
+func TestLoad_CircuitBreaker(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Breaker != (BreakerConfig{FailureThreshold: 5, OpenTimeout: 30 * time.Second}) {
+		t.Errorf("Breaker = %+v, want default {5 30s}", cfg.Breaker)
+	}
+
+	t.Setenv("TP_BREAKER_THRESHOLD", "0")
+	t.Setenv("TP_BREAKER_OPEN_TIMEOUT", "0s")
+	if _, err := Load(); err != nil {
+		t.Errorf("Load() with the breaker disabled: unexpected error: %v", err)
+	}
+
+	t.Setenv("TP_BREAKER_THRESHOLD", "3")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected an error for an enabled breaker without an open timeout")
+	}
+}
+
+func TestLoad_Cache(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Cache.Enabled {
+		t.Error("Cache.Enabled = true, want the cache off by default")
+	}
+
+	t.Setenv("TP_CACHE", "true")
+	t.Setenv("TP_CACHE_MAX_ENTRIES", "200")
+	t.Setenv("TP_CACHE_ENTITY_TTL", "10s")
+	t.Setenv("TP_CACHE_LIST_TTL", "0s")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	want := CacheConfig{Enabled: true, MaxEntries: 200, EntityTTL: 10 * time.Second, MetadataTTL: time.Hour}
+	if cfg.Cache != want {
+		t.Errorf("Cache = %+v, want %+v", cfg.Cache, want)
+	}
+
+	t.Setenv("TP_CACHE_MAX_ENTRIES", "0")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected an error for an enabled cache without entries")
+	}
+
+	cfg, err = Load(WithCache(false))
+	if err != nil {
+		t.Fatalf("Load() with the cache disabled: unexpected error: %v", err)
+	}
+	if cfg.Cache.Enabled {
+		t.Error("Cache.Enabled = true after WithCache(false), want false")
+	}
+}
+
+func TestLoad_MaxBatchSize(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+	t.Setenv("TP_MAX_BATCH_SIZE", "20")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Limits.MaxBatchSize != 20 {
+		t.Errorf("Limits.MaxBatchSize = %d, want 20", cfg.Limits.MaxBatchSize)
+	}
+
+	t.Setenv("TP_MAX_BATCH_SIZE", "0")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected an error for a zero batch size")
+	}
+}
+
+func TestLoad_AuthMethod(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "onprem.example.com")
+
+	cfg, err := Load(WithAccessToken("t"))
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Auth.Method != AuthAccessToken {
+		t.Errorf("Auth.Method = %q, want %q by default", cfg.Auth.Method, AuthAccessToken)
+	}
+
+	t.Setenv("TP_AUTH_METHOD", "basic")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected an error for basic auth without credentials")
+	}
+
+	t.Setenv("TP_USERNAME", "admin")
+	t.Setenv("TP_PASSWORD", "secret")
+	t.Setenv("TP_INSTANCES", "staging")
+	t.Setenv("TP_INSTANCE_STAGING_DOMAIN", "staging.example.com")
+	t.Setenv("TP_INSTANCE_STAGING_USERNAME", "bot")
+	t.Setenv("TP_INSTANCE_STAGING_PASSWORD", "bot-secret")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Auth.Method != AuthBasic || cfg.Auth.Username != "admin" || cfg.Auth.Password != "secret" {
+		t.Errorf("Auth = %+v", cfg.Auth)
+	}
+	staging := cfg.ForInstance(cfg.Instances[0])
+	if staging.Auth.Username != "bot" || staging.Auth.Password != "bot-secret" {
+		t.Errorf("ForInstance Auth = %+v, want the instance's credentials", staging.Auth)
+	}
+
+	t.Setenv("TP_INSTANCE_STAGING_PASSWORD", "")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected an error for an instance without a password")
+	}
+
+	if _, err := Load(WithAuthMethod("oauth")); err == nil {
+		t.Error("Load() expected an error for an unknown auth method")
+	}
+}
+
+func TestLoad_AccessTokenSource(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "test.tpondemand.com")
+	t.Setenv("TP_ACCESS_TOKEN_FILE", "/run/secrets/tp-token")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.AccessTokenFile != "/run/secrets/tp-token" || !cfg.HasCredentials() {
+		t.Errorf("AccessTokenFile = %q, HasCredentials = %t", cfg.AccessTokenFile, cfg.HasCredentials())
+	}
+	if cfg.Auth.TokenRefresh != 5*time.Minute {
+		t.Errorf("Auth.TokenRefresh = %s, want default 5m", cfg.Auth.TokenRefresh)
+	}
+
+	t.Setenv("TP_ACCESS_TOKEN_COMMAND", "vault read -field=token secret/tp")
+	if _, err := Load(); err == nil {
+		t.Error("Load() expected an error when both a token file and command are set")
+	}
+
+	t.Setenv("TP_ACCESS_TOKEN_FILE", "")
+	t.Setenv("TP_ACCESS_TOKEN_REFRESH", "1m")
+	t.Setenv("TP_INSTANCES", "sandbox")
+	t.Setenv("TP_INSTANCE_SANDBOX_DOMAIN", "sandbox.tpondemand.com")
+	t.Setenv("TP_INSTANCE_SANDBOX_ACCESS_TOKEN_FILE", "/run/secrets/sandbox-token")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Auth.TokenRefresh != time.Minute {
+		t.Errorf("Auth.TokenRefresh = %s, want 1m", cfg.Auth.TokenRefresh)
+	}
+	sandbox := cfg.ForInstance(cfg.Instances[0])
+	if sandbox.AccessTokenFile != "/run/secrets/sandbox-token" || sandbox.AccessTokenCommand != "" {
+		t.Errorf("ForInstance token source = %q / %q, want the instance's own file", sandbox.AccessTokenFile, sandbox.AccessTokenCommand)
+	}
+}
+
+func TestLoad_Endpoint(t *testing.T) {
+	t.Setenv("TP_DOMAIN", "tp.corp:8443")
+	t.Setenv("TP_ACCESS_TOKEN", "test-token-123")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Endpoint != (EndpointConfig{Scheme: "https"}) {
+		t.Errorf("Endpoint = %+v, want default https at the root", cfg.Endpoint)
+	}
+
+	t.Setenv("TP_SCHEME", "http")
+	t.Setenv("TP_BASE_PATH", "/targetprocess")
+	t.Setenv("TP_PROXY", "http://proxy.corp:3128")
+	t.Setenv("TP_CA_FILE", "/etc/ssl/corp-ca.pem")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	want := EndpointConfig{Scheme: "http", BasePath: "/targetprocess", Proxy: "http://proxy.corp:3128", CAFile: "/etc/ssl/corp-ca.pem"}
+	if cfg.Endpoint != want {
+		t.Errorf("Endpoint = %+v, want %+v", cfg.Endpoint, want)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		val  string
+	}{
+		{"unknown scheme", "TP_SCHEME", "ftp"},
+		{"relative base path", "TP_BASE_PATH", "targetprocess"},
+		{"proxy without scheme", "TP_PROXY", "proxy.corp:3128"},
+		{"client cert without key", "TP_CLIENT_CERT", "/etc/ssl/client.pem"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.val)
+			if _, err := Load(); err == nil {
+				t.Error("Load() expected error, got nil")
+			}
+		})
+	}
+}
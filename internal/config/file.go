@@ -0,0 +1,30 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readFile overlays the YAML or JSON config file at path onto c. Keys use the
+// yaml tags of Config (e.g. retry.initialDelay); keys that are absent keep
+// their current value and unknown keys are rejected, so typos fail loudly.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder serves both formats
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	c.File = path
+	return nil
+}
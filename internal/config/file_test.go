@@ -0,0 +1,225 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes content to name in a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFile_YAML(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+domain: file.tpondemand.com
+accessToken: file-token
+retry:
+  maxRetries: 5
+  initialDelay: 250ms
+  backoffFactor: 1.5
+timeouts:
+  http: 10s
+  tool: 45s
+limits:
+  maxAttachmentSize: 1048576
+  searchTake:
+    default: 50
+    max: 200
+instances:
+  - name: sandbox
+    domain: sandbox.tpondemand.com
+    accessToken: sandbox-token
+`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() unexpected error: %v", err)
+	}
+
+	if cfg.Domain != "file.tpondemand.com" {
+		t.Errorf("Domain = %q, want %q", cfg.Domain, "file.tpondemand.com")
+	}
+	if cfg.Retry.MaxRetries != 5 || cfg.Retry.InitialDelay != 250*time.Millisecond || cfg.Retry.BackoffFactor != 1.5 {
+		t.Errorf("Retry = %+v, want {5 250ms 1.5}", cfg.Retry)
+	}
+	if cfg.Timeouts.HTTP != 10*time.Second {
+		t.Errorf("Timeouts.HTTP = %v, want 10s", cfg.Timeouts.HTTP)
+	}
+	if cfg.Timeouts.Tool != 45*time.Second {
+		t.Errorf("Timeouts.Tool = %v, want 45s", cfg.Timeouts.Tool)
+	}
+	if cfg.Limits.MaxAttachmentSize != 1048576 {
+		t.Errorf("Limits.MaxAttachmentSize = %d, want 1048576", cfg.Limits.MaxAttachmentSize)
+	}
+	if cfg.Limits.SearchTake != (TakeLimit{Default: 50, Max: 200}) {
+		t.Errorf("Limits.SearchTake = %+v, want {50 200}", cfg.Limits.SearchTake)
+	}
+	// Keys absent from the file keep their defaults
+	if cfg.Limits.CommentsTake != DefaultLimits().CommentsTake {
+		t.Errorf("Limits.CommentsTake = %+v, want default", cfg.Limits.CommentsTake)
+	}
+	if len(cfg.Instances) != 1 || cfg.Instances[0].Name != "sandbox" {
+		t.Errorf("Instances = %+v, want one sandbox instance", cfg.Instances)
+	}
+	if cfg.File != path {
+		t.Errorf("File = %q, want %q", cfg.File, path)
+	}
+}
+
+func TestLoadFile_JSON(t *testing.T) {
+	path := writeFile(t, "config.json", `{
+  "domain": "file.tpondemand.com",
+  "accessToken": "file-token",
+  "retry": {"maxRetries": 0, "initialDelay": "2s", "backoffFactor": 3}
+}`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() unexpected error: %v", err)
+	}
+	if cfg.Retry.MaxRetries != 0 || cfg.Retry.InitialDelay != 2*time.Second || cfg.Retry.BackoffFactor != 3 {
+		t.Errorf("Retry = %+v, want {0 2s 3}", cfg.Retry)
+	}
+}
+
+func TestLoadFile_EnvOverridesFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+domain: file.tpondemand.com
+accessToken: file-token
+retry:
+  maxRetries: 5
+timeouts:
+  http: 10s
+`)
+	t.Setenv("TP_DOMAIN", "env.tpondemand.com")
+	t.Setenv("TP_MAX_RETRIES", "1")
+	t.Setenv("TP_HTTP_TIMEOUT", "45s")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() unexpected error: %v", err)
+	}
+	if cfg.Domain != "env.tpondemand.com" {
+		t.Errorf("Domain = %q, want env value", cfg.Domain)
+	}
+	if cfg.AccessToken != "file-token" {
+		t.Errorf("AccessToken = %q, want file value", cfg.AccessToken)
+	}
+	if cfg.Retry.MaxRetries != 1 {
+		t.Errorf("Retry.MaxRetries = %d, want env value 1", cfg.Retry.MaxRetries)
+	}
+	if cfg.Timeouts.HTTP != 45*time.Second {
+		t.Errorf("Timeouts.HTTP = %v, want env value 45s", cfg.Timeouts.HTTP)
+	}
+}
+
+func TestLoad_ReadsTPConfig(t *testing.T) {
+	t.Setenv("TP_CONFIG", writeFile(t, "config.yaml", "domain: file.tpondemand.com\naccessToken: file-token\n"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.Domain != "file.tpondemand.com" {
+		t.Errorf("Domain = %q, want %q", cfg.Domain, "file.tpondemand.com")
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown key",
+			content: "domain: x.tpondemand.com\naccessToken: t\nretries: 3\n",
+			wantErr: "field retries not found",
+		},
+		{
+			name:    "bad duration",
+			content: "domain: x.tpondemand.com\naccessToken: t\ntimeouts:\n  http: soon\n",
+			wantErr: "invalid config file",
+		},
+		{
+			name:    "take default above max",
+			content: "domain: x.tpondemand.com\naccessToken: t\nlimits:\n  commentsTake:\n    default: 200\n",
+			wantErr: "limits.commentsTake.max (100) must not be below limits.commentsTake.default (200)",
+		},
+		{
+			name:    "non-positive timeout",
+			content: "domain: x.tpondemand.com\naccessToken: t\ntimeouts:\n  http: 0s\n",
+			wantErr: "timeouts.http must be positive",
+		},
+		{
+			name:    "jitter out of range",
+			content: "domain: x.tpondemand.com\naccessToken: t\nretry:\n  jitter: 1.5\n",
+			wantErr: "retry.jitter must be between 0 and 1",
+		},
+		{
+			name:    "rate limit without burst",
+			content: "domain: x.tpondemand.com\naccessToken: t\nrateLimit:\n  requestsPerSecond: 5\n  burst: 0\n",
+			wantErr: "rateLimit.burst must be at least 1",
+		},
+		{
+			name:    "negative tool timeout",
+			content: "domain: x.tpondemand.com\naccessToken: t\ntimeouts:\n  tool: -1s\n",
+			wantErr: "timeouts.tool must not be negative",
+		},
+		{
+			name:    "invalid env value",
+			content: "domain: x.tpondemand.com\naccessToken: t\n",
+			env:     map[string]string{"TP_RETRY_DELAY": "5"},
+			wantErr: "invalid TP_RETRY_DELAY value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := LoadFile(writeFile(t, "config.yaml", tt.content))
+			if err == nil {
+				t.Fatal("LoadFile() expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFile_MissingFile(t *testing.T) {
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("LoadFile() expected error for missing file, got nil")
+	}
+}
+
+func TestTakeLimit_Clamp(t *testing.T) {
+	tests := []struct {
+		limit TakeLimit
+		n     int
+		want  int
+	}{
+		{TakeLimit{Default: 25, Max: 100}, 0, 1},
+		{TakeLimit{Default: 25, Max: 100}, 50, 50},
+		{TakeLimit{Default: 25, Max: 100}, 500, 100},
+		{TakeLimit{Default: 100}, 5000, 5000},
+	}
+	for _, tt := range tests {
+		if got := tt.limit.Clamp(tt.n); got != tt.want {
+			t.Errorf("%+v.Clamp(%d) = %d, want %d", tt.limit, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import "fmt"
+
+// LimitsConfig caps how much data a single tool call may request
+type LimitsConfig struct {
+	// MaxAttachmentSize is the largest attachment download_attachment returns, in bytes
+	MaxAttachmentSize int64 `yaml:"maxAttachmentSize"`
+
+	// MaxBatchSize caps the items of one bulk_create_entities or
+	// bulk_update_entities call
+	MaxBatchSize int `yaml:"maxBatchSize"`
+
+	SearchTake      TakeLimit `yaml:"searchTake"`
+	CommentsTake    TakeLimit `yaml:"commentsTake"`
+	AttachmentsTake TakeLimit `yaml:"attachmentsTake"`
+}
+
+// TakeLimit is the default and maximum page size of a listing tool
+type TakeLimit struct {
+	Default int `yaml:"default"`
+	// Max clamps the requested take; 0 means no limit
+	Max int `yaml:"max"`
+}
+
+// DefaultLimits returns the limits used when none are configured
+func DefaultLimits() LimitsConfig {
+	return LimitsConfig{
+		MaxAttachmentSize: 50 * 1024 * 1024,
+		MaxBatchSize:      50,
+		SearchTake:        TakeLimit{Default: 100, Max: 1000},
+		CommentsTake:      TakeLimit{Default: 25, Max: 100},
+		AttachmentsTake:   TakeLimit{Default: 100},
+	}
+}
+
+// validate checks that every limit is usable
+func (l LimitsConfig) validate() error {
+	if l.MaxAttachmentSize <= 0 {
+		return fmt.Errorf("limits.maxAttachmentSize must be positive, got %d", l.MaxAttachmentSize)
+	}
+	if l.MaxBatchSize < 1 {
+		return fmt.Errorf("limits.maxBatchSize must be at least 1, got %d", l.MaxBatchSize)
+	}
+	takes := []struct {
+		name  string
+		limit TakeLimit
+	}{
+		{"limits.searchTake", l.SearchTake},
+		{"limits.commentsTake", l.CommentsTake},
+		{"limits.attachmentsTake", l.AttachmentsTake},
+	}
+	for _, tt := range takes {
+		name, t := tt.name, tt.limit
+		if t.Default < 1 {
+			return fmt.Errorf("%s.default must be at least 1, got %d", name, t.Default)
+		}
+		if t.Max != 0 && t.Max < t.Default {
+			return fmt.Errorf("%s.max (%d) must not be below %s.default (%d)", name, t.Max, name, t.Default)
+		}
+	}
+	return nil
+}
+
+// Clamp limits a requested take to at least 1 and at most Max
+func (t TakeLimit) Clamp(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if t.Max != 0 && n > t.Max {
+		return t.Max
+	}
+	return n
+}
@@ -11,22 +11,30 @@ const GettingStarted = `# TP MCP Server - Getting Started
 2. Run the server:
    TP_DOMAIN=company.tpondemand.com TP_ACCESS_TOKEN=your-token ./server
 
-3. The server communicates via stdio using the MCP protocol.
+3. The server communicates via stdio using the MCP protocol. For a shared
+   deployment, run it with --transport=http to serve MCP over Streamable HTTP
+   at /mcp (legacy SSE clients use /sse).
 
 ## Available Tools
 
 | Tool | Description |
 |------|-------------|
-| search | Search for entities across 17 entity types with filters |
+| search | Search for entities of any entity type with filters |
+| query_v2 | Query with v2 projections and aggregates |
 | get_entity | Retrieve a single entity by type and ID |
 | create_entity | Create a new entity |
 | update_entity | Update an existing entity |
+| change_state | Move an entity to a workflow state by name |
+| bulk_create_entities | Create many entities in one request |
+| bulk_update_entities | Update many entities in one request |
+| delete_entity | Delete an entity after a confirmed preview |
 | add_comment | Add a comment to an entity |
 | list_comments | List comments on an entity |
 | list_attachments | List attachments on an entity |
 | download_attachment | Download an attachment by ID |
 | inspect_object | Inspect entity types and API metadata |
 | get_documentation | Access this documentation |
+| get_diagnostics | Show the connection state of each instance |
 
 ## Available Documentation Topics
 
@@ -54,7 +62,7 @@ const ToolReference = `# Tool Reference
 
 ## search
 
-Search for entities across 17 entity types with flexible filtering.
+Search for entities of any entity type with flexible filtering.
 
 **Parameters:**
 - entity_type (required): string - Type of entity to search (e.g., "UserStory", "Bug", "Task")
@@ -72,6 +80,27 @@ Search for entities across 17 entity types with flexible filtering.
 **Example:**
 search(entity_type="UserStory", where="EntityState.Name eq 'Open'", take=10)
 
+## query_v2
+
+Query entities through the v2 API, which returns only the fields you select,
+renamed and shaped as you ask, including nested fields and aggregates.
+
+**Parameters:**
+- type (required): string - Type of entity to query (e.g., "UserStory", "Bug")
+- select (optional): string - v2 projection, e.g. {id,name,state:entityState.name}
+- where (optional): string - v2 filter expression, e.g. entityState.isFinal==false
+- orderBy (optional): string - v2 sort expression, e.g. "createDate desc"
+- take (optional): integer - Number of results to return (default: 100, max: 1000)
+- skip (optional): integer - Number of results to skip
+- cursor (optional): string - Pagination cursor from a previous response
+
+v2 syntax is not the v1 syntax used by search: field names are camelCase and
+filters use ==, !=, <, >, and, or. Collections support where(...), count, sum(...)
+and other aggregates inside select.
+
+**Example:**
+query_v2(type="UserStory", select="{id,name,state:entityState.name,openTasks:tasks.where(entityState.isFinal==false).count}", where="project.id==123")
+
 ## get_entity
 
 Retrieve a single entity by type and ID.
@@ -107,6 +136,65 @@ Update an existing entity.
 **Example:**
 update_entity(entity_type="UserStory", id=1234, data={"EntityState": {"Id": 5}})
 
+## change_state
+
+Move an entity to a workflow state by name. The name is matched, ignoring
+case, against the states of the entity's responsible team's workflow, if it
+has one, then against the states the entity's project process defines for
+its type; transitions the workflow does not allow are rejected with the
+allowed ones.
+
+**Parameters:**
+- type (required): string - Entity type
+- id (required): integer - Entity ID
+- state (required): string - Name of the target state
+- comment (optional): string - Comment to add explaining the change
+- dryRun (optional): boolean - Return the request without sending it
+
+**Example:**
+change_state(type="Bug", id=1234, state="In Progress", comment="Reproduced, starting work")
+
+## bulk_create_entities
+
+Create many entities of one type in a single request. Each item reports its
+own success or error, so one rejected item does not fail the rest.
+
+**Parameters:**
+- type (required): string - Type of entity to create
+- items (required): array - Entity data objects, each including Name (at most 50 by default)
+- dryRun (optional): boolean - Return the request without sending it
+
+**Example:**
+bulk_create_entities(type="Task", items=[{"Name": "Write tests", "UserStory": {"Id": 1234}}, {"Name": "Update docs", "UserStory": {"Id": 1234}}])
+
+## bulk_update_entities
+
+Update many entities of one type in a single request, with a result per item.
+
+**Parameters:**
+- type (required): string - Type of entity to update
+- items (required): array - Objects with the entity Id and the fields to change
+- dryRun (optional): boolean - Return the request without sending it
+
+**Example:**
+bulk_update_entities(type="Bug", items=[{"Id": 101, "EntityState": {"Id": 5}}, {"Id": 102, "EntityState": {"Id": 5}}])
+
+## delete_entity
+
+Delete an entity in two calls. The first, without confirmationToken, returns
+the entity and a token valid for 5 minutes; the second passes the token back
+and deletes the entity, returning its default fields.
+
+**Parameters:**
+- type (required): string - Type of entity to delete
+- id (required): integer - Entity ID
+- confirmationToken (optional): string - Token from the preview call; omit to preview
+- dryRun (optional): boolean - Return the request without sending it
+
+**Example:**
+delete_entity(type="Bug", id=1234)
+delete_entity(type="Bug", id=1234, confirmationToken="...")
+
 ## add_comment
 
 Add a comment to an entity.
@@ -166,6 +254,55 @@ Inspect entity types and API metadata.
 **Example:**
 inspect_object(object_type="UserStory")
 
+## Selecting an Instance
+
+Every tool that calls Target Process accepts an optional instance parameter
+naming the Target Process instance to use. List the configured instances with
+inspect_object(action="list_instances").
+
+**Example:**
+get_entity(type="Bug", id=123, instance="sandbox")
+
+## Errors
+
+A failed call reports the HTTP status, Target Process's own message and a hint
+for that kind of failure:
+- 400: the query syntax, or for writes the fields TP rejected by name
+- 401/403: the token was rejected, or the user lacks access
+- 404: the entity does not exist
+- 409: the entity changed underneath the update; fetch it again and retry
+- 429: TP is rate limiting; the hint says how long to wait
+
+## Cached Responses
+
+When the server runs with its response cache on, get_entity, list_comments,
+list_attachments and inspect_object may answer from memory. Writes made
+through this server refresh what they change, but edits made in Target
+Process directly can take a minute to show. Pass noCache=true to fetch fresh
+data.
+
+**Example:**
+get_entity(type="Bug", id=123, noCache=true)
+
+## Previewing Writes
+
+create_entity, update_entity, change_state, the bulk tools, delete_entity and add_comment accept an optional dryRun
+parameter. With dryRun=true nothing is sent; the tool returns the method, URL
+and payload it would have used, and update_entity adds a diff of each changed
+field against the current entity.
+
+**Example:**
+update_entity(entity_type="Bug", id=123, data={"Name": "New name"}, dryRun=true)
+
+## get_diagnostics
+
+Show the circuit breaker state of each Target Process instance. When TP fails
+repeatedly the breaker opens and calls fail fast with a circuit breaker error
+until retryIn has passed.
+
+**Example:**
+get_diagnostics()
+
 ## get_documentation
 
 Access this documentation system.
@@ -209,6 +346,11 @@ create_entity(
 
 Move a user story to "In Progress":
 
+change_state(type="UserStory", id=1234, state="In Progress")
+
+Setting the state ID directly with update_entity also works, but skips the
+workflow check:
+
 update_entity(
   entity_type="UserStory",
   id=1234,
@@ -545,14 +687,34 @@ set TP_DOMAIN=company.tpondemand.com
 set TP_ACCESS_TOKEN=your-access-token-here
 server.exe
 
+## Authentication Methods
+
+TP_AUTH_METHOD selects how requests are authenticated:
+
+- **access_token** (default): the token is sent as the access_token query parameter
+- **bearer**: the token is sent in an "Authorization: Bearer" header, so it never appears in URLs, proxy logs or error messages
+- **basic**: HTTP Basic auth with TP_USERNAME and TP_PASSWORD instead of a token, for on-premise installs
+
+## Rotating Tokens
+
+Set TP_ACCESS_TOKEN_FILE or TP_ACCESS_TOKEN_COMMAND instead of TP_ACCESS_TOKEN
+to read the token from a file or a command's output. The token is re-read every
+TP_ACCESS_TOKEN_REFRESH (default 5m) and whenever Target Process rejects it, so
+a rotated token is picked up without restarting the server.
+
 ## Domain Format
 
 The domain should be your Target Process hostname without the protocol:
 
 ✓ Correct: company.tpondemand.com
+✓ Correct: tp.corp:8443 (self-hosted, non-standard port)
 ✗ Incorrect: https://company.tpondemand.com
 ✗ Incorrect: https://company.tpondemand.com/
 
+Self-hosted installs can set TP_SCHEME (http or https), TP_BASE_PATH (e.g.
+/targetprocess), TP_PROXY, TP_CA_FILE for an internal CA, and TP_CLIENT_CERT
+with TP_CLIENT_KEY for mutual TLS.
+
 ## Security Best Practices
 
 1. **Never commit tokens**: Do not commit access tokens to version control
@@ -590,7 +752,11 @@ The search tool is the most powerful tool in the TP MCP Server, allowing you to
 
 ## Supported Entity Types
 
-The search tool supports 17 entity types:
+The tools accept every entity type the Target Process instance reports in its
+metadata, including custom entity types; inspect_object with action list_types
+lists them. The type argument's schema lists only the standard types below,
+which are also what is accepted when the metadata cannot be read. Common ones
+include:
 
 - UserStory: User stories and requirements
 - Bug: Bug reports
@@ -735,10 +901,13 @@ const entityContent = `# Entity Management
 
 ## Overview
 
-The TP MCP Server provides three tools for entity management:
+The TP MCP Server provides these tools for entity management:
 - get_entity: Retrieve a single entity
 - create_entity: Create a new entity
 - update_entity: Update an existing entity
+- bulk_create_entities, bulk_update_entities: Create or update many entities at once
+- change_state: Move an entity to a workflow state by name
+- delete_entity: Delete an entity after a confirmed preview
 
 ## get_entity
 
@@ -879,6 +1048,60 @@ update_entity(
   }
 )
 
+## Changing State
+
+change_state moves an entity through its workflow by state name, so the
+numeric EntityState ID is not needed. It reads the entity's current state
+and its project's process, loads that process's states for the entity type,
+and checks that the workflow allows the move:
+
+{"id": 1234, "previousState": "Open", "state": "In Progress"}
+
+A misspelled name gets a suggestion, and a move the workflow does not allow
+lists the states that are allowed from the current one. When the entity's
+responsible team has its own workflow, naming one of its states moves the
+team state instead, following the team workflow's transitions, and the
+result names the team:
+
+{"id": 1234, "previousState": "Coding", "state": "Code Review", "team": "Alpha"}
+
+States of a team workflow the entity does not follow are rejected with the
+process state they belong to. Pass comment to record why; if the comment
+fails after the state changed, the result carries commentError instead of
+failing the call. In a dry run, commentRequest previews the comment as well.
+
+## Bulk Writes
+
+bulk_create_entities and bulk_update_entities send up to 50 items (the
+server's configured batch size) in one request. The result counts successes
+and failures and lists every item in request order:
+
+{"succeeded": 1, "failed": 1, "results": [
+  {"index": 0, "success": true, "entity": {"Id": 101, ...}},
+  {"index": 1, "success": false, "error": "Entity 102 not found"}
+]}
+
+Retry only the failed items. A bulk create that times out is not retried
+automatically; check which entities exist before sending it again.
+
+## Deleting Entities
+
+delete_entity never deletes on the first call. Without a confirmationToken it
+returns the entity as it stands and a token bound to that entity and
+instance:
+
+{"request": {"method": "DELETE", "url": ".../api/v1/Bugs/1234"},
+ "entity": {"Id": 1234, "Name": "...", ...},
+ "confirmationToken": "...", "expiresIn": "5m0s"}
+
+Check the entity, then call delete_entity again with the token. Tokens expire
+after 5 minutes and do not survive a server restart. The response, like the
+audit log, carries the deleted entity's default fields, custom fields
+included; pass them to create_entity to recreate it (under a new ID).
+Collections such as assigned users, comments and attachments are not part of
+the snapshot and have to be restored separately. Read-only mode removes the
+tool.
+
 ## Field Reference by Type
 
 Entities reference related items by Id:
@@ -891,6 +1114,25 @@ Entities reference related items by Id:
 
 Use inspect_object to discover available fields for each entity type.
 
+## Custom Fields
+
+create_entity checks customFields before sending anything, against the
+custom fields of the project's process (when no project is given, a value
+passes if any process accepts it): each value of a custom field must suit
+the field's type, dropdown values must be one of the options, and a name that
+is a near miss of a custom field name is rejected. Other names, such as
+standard fields, are sent as given. With a
+project, required custom fields must be set too. update_entity checks the
+custom fields in its fields object the same way, against the process of the
+entity's project. Every problem is reported at once, with suggestions for
+near misses:
+
+Error: custom field check failed, nothing was sent:
+validation error on field Rsk: unknown custom field; did you mean "Risk"?
+
+List the definitions with inspect_object(action="list_custom_fields",
+entityType="Bug").
+
 ## Tips
 
 1. Use get_entity to see current values before updating
@@ -1189,6 +1431,23 @@ Returns detailed schema for UserStory including:
 - Required fields
 - Relationships
 
+### List Target Process Instances
+
+inspect_object(action="list_instances")
+
+Returns the instances this server can reach, e.g. prod and sandbox, and marks
+the default one. Pass instance="sandbox" to any tool that calls Target
+Process to run it against that instance; without it, tools use the default instance.
+
+### List Custom Fields
+
+inspect_object(action="list_custom_fields", entityType="UserStory")
+
+Returns each custom field defined for the type: its name, type (Text, Number,
+DropDown, CheckBox, Date, ...), dropdown options, whether it is required, and
+the process defining it. create_entity and update_entity check payloads
+against these definitions.
+
 ## Use Cases
 
 ### Discover Available Fields
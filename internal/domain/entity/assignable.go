@@ -0,0 +1,102 @@
+package entity
+
+// State is an entity's workflow state
+type State struct {
+	ID        int    `json:"Id"`
+	Name      string `json:"Name"`
+	IsInitial bool   `json:"IsInitial,omitempty"`
+	IsFinal   bool   `json:"IsFinal,omitempty"`
+}
+
+// CustomField is the value of a process-defined field on an entity
+type CustomField struct {
+	Name  string `json:"Name"`
+	Type  string `json:"Type"`
+	Value any    `json:"Value"`
+}
+
+// Assignable holds the fields shared by the work items people are assigned
+// to: user stories, bugs, tasks, features and epics
+type Assignable struct {
+	ID              int     `json:"Id"`
+	Name            string  `json:"Name"`
+	Description     string  `json:"Description,omitempty"`
+	ResourceType    string  `json:"ResourceType,omitempty"`
+	CreateDate      Date    `json:"CreateDate"`
+	ModifyDate      Date    `json:"ModifyDate"`
+	StartDate       Date    `json:"StartDate"`
+	EndDate         Date    `json:"EndDate"`
+	NumericPriority float64 `json:"NumericPriority,omitempty"`
+	Tags            Tags    `json:"Tags,omitempty"`
+
+	Effort          float64 `json:"Effort"`
+	EffortCompleted float64 `json:"EffortCompleted"`
+	EffortToDo      float64 `json:"EffortToDo"`
+	TimeSpent       float64 `json:"TimeSpent"`
+	TimeRemain      float64 `json:"TimeRemain"`
+
+	Project      *Ref             `json:"Project,omitempty"`
+	Team         *Ref             `json:"Team,omitempty"`
+	Release      *Ref             `json:"Release,omitempty"`
+	Iteration    *Ref             `json:"Iteration,omitempty"`
+	Priority     *Ref             `json:"Priority,omitempty"`
+	EntityState  *State           `json:"EntityState,omitempty"`
+	Owner        *User            `json:"Owner,omitempty"`
+	AssignedUser Collection[User] `json:"AssignedUser"`
+	CustomFields []CustomField    `json:"CustomFields,omitempty"`
+}
+
+// UserStory represents a user story in TargetProcess
+type UserStory struct {
+	Assignable
+	Feature *Ref `json:"Feature,omitempty"`
+}
+
+// Bug represents a bug in TargetProcess
+type Bug struct {
+	Assignable
+	UserStory *Ref `json:"UserStory,omitempty"`
+	Severity  *Ref `json:"Severity,omitempty"`
+}
+
+// Task represents a task in TargetProcess
+type Task struct {
+	Assignable
+	UserStory *Ref `json:"UserStory,omitempty"`
+}
+
+// Feature represents a feature in TargetProcess
+type Feature struct {
+	Assignable
+	Epic *Ref `json:"Epic,omitempty"`
+}
+
+// Epic represents an epic in TargetProcess
+type Epic struct {
+	Assignable
+}
+
+// Iteration represents an iteration (sprint) in TargetProcess
+type Iteration struct {
+	ID          int     `json:"Id"`
+	Name        string  `json:"Name"`
+	Description string  `json:"Description,omitempty"`
+	StartDate   Date    `json:"StartDate"`
+	EndDate     Date    `json:"EndDate"`
+	IsCurrent   bool    `json:"IsCurrent"`
+	Velocity    float64 `json:"Velocity"`
+	Project     *Ref    `json:"Project,omitempty"`
+	Release     *Ref    `json:"Release,omitempty"`
+}
+
+// Release represents a release in TargetProcess
+type Release struct {
+	ID          int     `json:"Id"`
+	Name        string  `json:"Name"`
+	Description string  `json:"Description,omitempty"`
+	StartDate   Date    `json:"StartDate"`
+	EndDate     Date    `json:"EndDate"`
+	IsCurrent   bool    `json:"IsCurrent"`
+	Effort      float64 `json:"Effort"`
+	Project     *Ref    `json:"Project,omitempty"`
+}
@@ -0,0 +1,338 @@
+package entity
+
+import (
+	stderrors "errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"tp-mcp-go/internal/domain/errors"
+)
+
+// CustomFieldDefinition describes a custom field a process defines for an
+// entity type
+type CustomFieldDefinition struct {
+	Name string `json:"name"`
+	// Type is TP's field type, e.g. Text, Number, DropDown or CheckBox
+	Type string `json:"type"`
+	// Options lists the allowed values of DropDown and MultipleSelectionList fields
+	Options     []string `json:"options,omitempty"`
+	Required    bool     `json:"required"`
+	ProcessID   int      `json:"processId,omitempty"`
+	ProcessName string   `json:"processName,omitempty"`
+}
+
+// CustomFieldsForProcess returns the definitions of the process with the
+// given ID, or all of defs when processID is 0
+func CustomFieldsForProcess(defs []CustomFieldDefinition, processID int) []CustomFieldDefinition {
+	if processID == 0 {
+		return defs
+	}
+	var out []CustomFieldDefinition
+	for _, d := range defs {
+		if d.ProcessID == processID {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// ValidateCustomFields checks values, keyed by field name, against defs:
+// every value of a defined field must suit its type. values may hold
+// standard fields too, such as Severity or Priority, so an undefined name is
+// only reported when it is a near miss of a custom field name, as in
+// ValidateFields. defs should be those of one process; when they span
+// several, a value passes if any process's definition of its field accepts
+// it. With requireAll, required fields missing from values are reported
+// too. All problems are returned together, joined.
+func ValidateCustomFields(defs []CustomFieldDefinition, values map[string]any, requireAll bool) error {
+	byName := definitionsByName(defs)
+	var errs []error
+	for _, name := range sortedKeys(values) {
+		candidates, ok := byName[name]
+		if !ok {
+			if !standardFields[name] && closestName(name, defs) != "" {
+				errs = append(errs, unknownFieldError(name, defs))
+			}
+			continue
+		}
+		if err := checkValueAny(candidates, values[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if requireAll {
+		for _, d := range defs {
+			if _, ok := values[d.Name]; d.Required && !ok {
+				errs = append(errs, &errors.ValidationError{Field: d.Name, Message: "required custom field is missing"})
+			}
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// ValidateFields checks an update payload, which mixes standard and custom
+// fields. Fields named like a definition, and entries of a CustomFields
+// array, are checked as in ValidateCustomFields; any other field that is a
+// near miss of a custom field name is reported, since TP would reject it.
+func ValidateFields(defs []CustomFieldDefinition, fields map[string]any) error {
+	byName := definitionsByName(defs)
+	var errs []error
+	for _, name := range sortedKeys(fields) {
+		if name == "CustomFields" {
+			continue
+		}
+		if candidates, ok := byName[name]; ok {
+			if err := checkValueAny(candidates, fields[name]); err != nil {
+				errs = append(errs, err)
+			}
+		} else if !standardFields[name] && closestName(name, defs) != "" {
+			errs = append(errs, unknownFieldError(name, defs))
+		}
+	}
+	if list, ok := fields["CustomFields"].([]any); ok {
+		for _, item := range list {
+			entry, _ := item.(map[string]any)
+			name, _ := entry["Name"].(string)
+			if name == "" {
+				errs = append(errs, &errors.ValidationError{Field: "CustomFields", Message: "each entry needs a Name and a Value"})
+				continue
+			}
+			candidates, ok := byName[name]
+			if !ok {
+				errs = append(errs, unknownFieldError(name, defs))
+				continue
+			}
+			if err := checkValueAny(candidates, entry["Value"]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return stderrors.Join(errs...)
+}
+
+// standardFields are the fields of the typed models, which a near miss of a
+// custom field name must not be mistaken for
+var standardFields = jsonFieldNames(UserStory{}, Bug{}, Task{}, Feature{}, Epic{}, Iteration{}, Release{})
+
+func jsonFieldNames(models ...any) map[string]bool {
+	names := make(map[string]bool)
+	for _, m := range models {
+		for _, f := range reflect.VisibleFields(reflect.TypeOf(m)) {
+			if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// definitionsByName indexes defs by name, keeping every process's
+// definition of a field
+func definitionsByName(defs []CustomFieldDefinition) map[string][]CustomFieldDefinition {
+	byName := make(map[string][]CustomFieldDefinition, len(defs))
+	for _, d := range defs {
+		byName[d.Name] = append(byName[d.Name], d)
+	}
+	return byName
+}
+
+// checkValueAny reports whether value can be stored in the field under any
+// of its definitions, returning the first definition's complaint if none
+// accepts it
+func checkValueAny(candidates []CustomFieldDefinition, value any) error {
+	var first error
+	for _, def := range candidates {
+		err := checkValue(def, value)
+		if err == nil {
+			return nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func unknownFieldError(name string, defs []CustomFieldDefinition) error {
+	msg := "unknown custom field"
+	if suggestion := closestName(name, defs); suggestion != "" {
+		msg += fmt.Sprintf("; did you mean %q?", suggestion)
+	}
+	return &errors.ValidationError{Field: name, Message: msg}
+}
+
+// checkValue reports whether value can be stored in the field def. A nil
+// value clears the field and always passes; field types this package does
+// not know pass too, leaving TP to judge them.
+func checkValue(def CustomFieldDefinition, value any) error {
+	if value == nil {
+		return nil
+	}
+	invalid := func(format string, args ...any) error {
+		return &errors.ValidationError{Field: def.Name, Message: fmt.Sprintf(format, args...)}
+	}
+	switch def.Type {
+	case "Text", "RichText":
+		if _, ok := value.(string); !ok {
+			return invalid("%s field expects a string, got %T", def.Type, value)
+		}
+	case "Number", "Money":
+		if !isNumber(value) {
+			return invalid("%s field expects a number, got %v", def.Type, value)
+		}
+	case "CheckBox":
+		if !isBool(value) {
+			return invalid("CheckBox field expects true or false, got %v", value)
+		}
+	case "Date":
+		s, ok := value.(string)
+		if !ok || !isDate(s) {
+			return invalid("Date field expects a date such as 2024-01-31, got %v", value)
+		}
+	case "URL", "TemplatedURL":
+		switch value.(type) {
+		case string, map[string]any:
+		default:
+			return invalid("%s field expects a URL string, got %T", def.Type, value)
+		}
+	case "DropDown":
+		s, ok := value.(string)
+		if !ok {
+			return invalid("DropDown field expects one of its options as a string, got %T", value)
+		}
+		return checkOption(def, s)
+	case "MultipleSelectionList":
+		var selected []string
+		switch v := value.(type) {
+		case string:
+			selected = strings.Split(v, ",")
+		case []any:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return invalid("MultipleSelectionList field expects strings, got %T", item)
+				}
+				selected = append(selected, s)
+			}
+		default:
+			return invalid("MultipleSelectionList field expects a list of its options, got %T", value)
+		}
+		for _, s := range selected {
+			if err := checkOption(def, strings.TrimSpace(s)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkOption reports whether s is one of def's options
+func checkOption(def CustomFieldDefinition, s string) error {
+	if len(def.Options) == 0 {
+		return nil
+	}
+	for _, o := range def.Options {
+		if o == s {
+			return nil
+		}
+	}
+	msg := fmt.Sprintf("%q is not an option (options: %s)", s, strings.Join(def.Options, ", "))
+	if suggestion := closest(s, def.Options); suggestion != "" {
+		msg += fmt.Sprintf("; did you mean %q?", suggestion)
+	}
+	return &errors.ValidationError{Field: def.Name, Message: msg}
+}
+
+func isNumber(v any) bool {
+	switch n := v.(type) {
+	case float64, int:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(n, 64)
+		return err == nil
+	}
+	return false
+}
+
+func isBool(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return true
+	case string:
+		_, err := strconv.ParseBool(b)
+		return err == nil
+	}
+	return false
+}
+
+// isDate reports whether s is a date TP accepts: ISO 8601 or its own /Date(...)/ form
+func isDate(s string) bool {
+	if _, ok := ParseDate(s); ok {
+		return true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", time.DateOnly} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// closestName returns the definition name name most likely misspells, if any
+func closestName(name string, defs []CustomFieldDefinition) string {
+	names := make([]string, len(defs))
+	for i, d := range defs {
+		names[i] = d.Name
+	}
+	return closest(name, names)
+}
+
+// closest returns the candidate within a small edit distance of s, ignoring
+// case, or "" when none is close enough to be a likely typo
+func closest(s string, candidates []string) string {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		if c == s {
+			continue
+		}
+		d := editDistance(strings.ToLower(s), strings.ToLower(c))
+		if d <= max(1, len(c)/4) && (bestDist < 0 || d < bestDist) {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func testDefinitions() []CustomFieldDefinition {
+	return []CustomFieldDefinition{
+		{Name: "Risk", Type: "DropDown", Options: []string{"Low", "Medium", "High"}, Required: true, ProcessID: 1},
+		{Name: "Story Points", Type: "Number", ProcessID: 1},
+		{Name: "Customer Facing", Type: "CheckBox", ProcessID: 1},
+		{Name: "Due", Type: "Date", ProcessID: 2},
+		{Name: "Platforms", Type: "MultipleSelectionList", Options: []string{"iOS", "Android", "Web"}, ProcessID: 2},
+	}
+}
+
+func TestValidateCustomFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"Risk": "High", "Story Points": float64(3), "Customer Facing": true, "Due": "2024-01-31", "Platforms": []any{"iOS", "Web"}}, ""},
+		{"null clears", map[string]any{"Risk": nil}, ""},
+		{"misspelled name", map[string]any{"Story Pionts": float64(3)}, `Story Pionts: unknown custom field; did you mean "Story Points"?`},
+		{"wrong case", map[string]any{"risk": "High"}, `did you mean "Risk"?`},
+		{"other field passes", map[string]any{"Severity": "High", "Priority": "P1"}, ""},
+		{"wrong type", map[string]any{"Story Points": "three"}, "Number field expects a number"},
+		{"not an option", map[string]any{"Risk": "high"}, `"high" is not an option (options: Low, Medium, High); did you mean "High"?`},
+		{"not a list option", map[string]any{"Platforms": "iOS, Linux"}, `"Linux" is not an option`},
+		{"bad date", map[string]any{"Due": "next week"}, "Date field expects a date"},
+		{"bad checkbox", map[string]any{"Customer Facing": "yes please"}, "CheckBox field expects true or false"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustomFields(testDefinitions(), tt.values, false)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCustomFields_RequiredAndJoined(t *testing.T) {
+	defs := CustomFieldsForProcess(testDefinitions(), 1)
+	if len(defs) != 3 {
+		t.Fatalf("expected the 3 fields of process 1, got %+v", defs)
+	}
+	err := ValidateCustomFields(defs, map[string]any{"Story Points": "many"}, true)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "Risk: required custom field is missing") || !strings.Contains(msg, "Story Points") {
+		t.Errorf("expected every problem to be reported, got %q", msg)
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	defs := append(testDefinitions(), CustomFieldDefinition{Name: "Efforts", Type: "Text"})
+	tests := []struct {
+		name    string
+		fields  map[string]any
+		wantErr string
+	}{
+		{"standard fields pass", map[string]any{"Name": "New", "Effort": float64(3), "EntityState": map[string]any{"Id": 5}}, ""},
+		{"custom field checked", map[string]any{"Risk": "Severe"}, `"Severe" is not an option`},
+		{"near miss", map[string]any{"Rsk": "High"}, `did you mean "Risk"?`},
+		{"CustomFields array", map[string]any{"CustomFields": []any{map[string]any{"Name": "Story Points", "Value": true}}}, "Number field expects a number"},
+		{"CustomFields array unknown name", map[string]any{"CustomFields": []any{map[string]any{"Name": "Budget", "Value": 1}}}, "Budget: unknown custom field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFields(defs, tt.fields)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateCustomFields_AnyProcessDefinition(t *testing.T) {
+	defs := []CustomFieldDefinition{
+		{Name: "Risk", Type: "DropDown", Options: []string{"Low", "High"}, ProcessID: 3},
+		{Name: "Risk", Type: "DropDown", Options: []string{"Minor", "Major"}, ProcessID: 4},
+	}
+	if err := ValidateCustomFields(defs, map[string]any{"Risk": "Major"}, false); err != nil {
+		t.Errorf("expected a value the second process allows to pass, got %v", err)
+	}
+	if err := ValidateCustomFields(defs, map[string]any{"Risk": "Severe"}, false); err == nil {
+		t.Error("expected a value no process allows to fail")
+	}
+	if err := ValidateCustomFields(CustomFieldsForProcess(defs, 3), map[string]any{"Risk": "Major"}, false); err == nil {
+		t.Error("expected the value to fail against process 3 alone")
+	}
+}
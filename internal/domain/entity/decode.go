@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode converts a raw API object, as returned by GetEntity, into a T
+func Decode[T any](raw map[string]any) (*T, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode %T: %w", v, err)
+	}
+	return &v, nil
+}
+
+// DecodeAll converts raw API objects, such as search results, into Ts
+func DecodeAll[T any](items []map[string]any) ([]T, error) {
+	out := make([]T, 0, len(items))
+	for i, item := range items {
+		v, err := Decode[T](item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// datePattern matches the dates the TP API returns, e.g. /Date(1700000000000-0500)/
+var datePattern = regexp.MustCompile(`^/Date\((-?\d+)([+-]\d{4})?\)/$`)
+
+// ParseDate parses a TP API date; the milliseconds are since the Unix epoch in UTC
+func ParseDate(s string) (time.Time, bool) {
+	m := datePattern.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// Date is a TP timestamp. It decodes the v1 /Date(...)/ form as well as the
+// RFC 3339 strings of the v2 API, and encodes as RFC 3339. A null date is
+// the zero Date.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("date must be a string, got %s", data)
+	}
+	if t, ok := ParseDate(s); ok {
+		d.Time = t
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("unrecognized date %q", s)
+	}
+	d.Time = t
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return d.Time.MarshalJSON()
+}
+
+// Tags is an entity's tag list, which TP sends as one comma-separated string
+type Tags []string
+
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = nil
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var list []string
+		if err := json.Unmarshal(data, &list); err != nil {
+			return fmt.Errorf("tags must be a string, got %s", data)
+		}
+		*t = list
+		return nil
+	}
+	*t = nil
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+	return nil
+}
+
+// Collection is a to-many relation. The v1 API wraps it in an Items
+// envelope and the v2 API sends a bare array; both decode to Items.
+type Collection[T any] struct {
+	Items []T `json:"Items"`
+}
+
+func (c *Collection[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, &c.Items)
+	}
+	var envelope struct {
+		Items []T `json:"Items"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	c.Items = envelope.Items
+	return nil
+}
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	got, ok := ParseDate("/Date(1700000000000-0500)/")
+	if !ok || !got.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("ParseDate = %v, %v; want %v", got, ok, time.UnixMilli(1700000000000))
+	}
+	if _, ok := ParseDate("2023-11-14"); ok {
+		t.Error("expected non-TP date to be rejected")
+	}
+}
+
+func TestDate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want time.Time
+	}{
+		{"v1 format", `"/Date(1700000000000+0000)/"`, time.UnixMilli(1700000000000)},
+		{"RFC 3339", `"2023-11-14T22:13:20Z"`, time.UnixMilli(1700000000000)},
+		{"null", `null`, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			if err := json.Unmarshal([]byte(tt.json), &d); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !d.Equal(tt.want) {
+				t.Errorf("got %v, want %v", d.Time, tt.want)
+			}
+		})
+	}
+
+	var d Date
+	if err := json.Unmarshal([]byte(`"yesterday"`), &d); err == nil {
+		t.Error("expected an unrecognized date to fail")
+	}
+}
+
+func TestTags_UnmarshalJSON(t *testing.T) {
+	var tags Tags
+	if err := json.Unmarshal([]byte(`"backend, urgent,,api "`), &tags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 3 || tags[0] != "backend" || tags[1] != "urgent" || tags[2] != "api" {
+		t.Errorf("unexpected tags: %q", tags)
+	}
+}
+
+func TestCollection_UnmarshalJSON(t *testing.T) {
+	for _, data := range []string{`{"Items": [{"Id": 1}, {"Id": 2}]}`, `[{"Id": 1}, {"Id": 2}]`} {
+		var users Collection[User]
+		if err := json.Unmarshal([]byte(data), &users); err != nil {
+			t.Fatalf("unexpected error for %s: %v", data, err)
+		}
+		if len(users.Items) != 2 || users.Items[1].ID != 2 {
+			t.Errorf("unexpected users for %s: %+v", data, users.Items)
+		}
+	}
+}
+
+func TestDecode_UserStory(t *testing.T) {
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"Id": 42,
+		"Name": "Login page",
+		"CreateDate": "/Date(1700000000000-0500)/",
+		"EndDate": null,
+		"Tags": "auth, ui",
+		"Effort": 5.5,
+		"Project": {"Id": 7, "Name": "Web", "ResourceType": "Project"},
+		"EntityState": {"Id": 3, "Name": "In Progress", "IsFinal": false},
+		"AssignedUser": {"Items": [{"Id": 9, "FirstName": "Ada", "LastName": "Lovelace"}]},
+		"Feature": {"Id": 100, "Name": "Accounts"},
+		"CustomFields": [{"Name": "Risk", "Type": "DropDown", "Value": "High"}]
+	}`), &raw); err != nil {
+		t.Fatal(err)
+	}
+
+	story, err := Decode[UserStory](raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if story.ID != 42 || story.Name != "Login page" || story.Effort != 5.5 {
+		t.Errorf("unexpected fields: %+v", story)
+	}
+	if !story.CreateDate.Equal(time.UnixMilli(1700000000000)) || !story.EndDate.IsZero() {
+		t.Errorf("unexpected dates: %v, %v", story.CreateDate, story.EndDate)
+	}
+	if story.Project.ID != 7 || story.EntityState.Name != "In Progress" || story.Feature.ID != 100 {
+		t.Errorf("unexpected references: %+v %+v %+v", story.Project, story.EntityState, story.Feature)
+	}
+	if len(story.Tags) != 2 || len(story.AssignedUser.Items) != 1 || story.AssignedUser.Items[0].LastName != "Lovelace" {
+		t.Errorf("unexpected tags or assignees: %q %+v", story.Tags, story.AssignedUser)
+	}
+	if len(story.CustomFields) != 1 || story.CustomFields[0].Value != "High" {
+		t.Errorf("unexpected custom fields: %+v", story.CustomFields)
+	}
+}
+
+func TestDecodeAll_ReportsFailingItem(t *testing.T) {
+	_, err := DecodeAll[Bug]([]map[string]any{{"Id": 1}, {"Id": "two"}})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := err.Error(); got[:6] != "item 1" {
+		t.Errorf("expected the failing item to be named, got %q", got)
+	}
+}
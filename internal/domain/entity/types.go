@@ -2,7 +2,9 @@ package entity
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // Type represents a Target Process entity type
@@ -29,7 +31,8 @@ const (
 	TypeProgram        Type = "Program"
 )
 
-// ValidTypes contains all valid entity types
+// ValidTypes contains the standard entity types, accepted until TP's
+// metadata has been read (see RegisterTypes)
 var ValidTypes = []Type{
 	TypeUserStory,
 	TypeBug,
@@ -50,10 +53,49 @@ var ValidTypes = []Type{
 	TypeProgram,
 }
 
+// registry holds the entity types reported by TP's metadata
+var registry struct {
+	sync.RWMutex
+	types []Type
+}
+
+// Types returns the entity types tools accept: the types registered from
+// TP's metadata, or ValidTypes while none are
+func Types() []Type {
+	registry.RLock()
+	defer registry.RUnlock()
+	if len(registry.types) == 0 {
+		return slices.Clone(ValidTypes)
+	}
+	return slices.Clone(registry.types)
+}
+
+// RegisterTypes adds entity types read from an instance's metadata, such as
+// custom types or User and Time, to the registry. Once any are registered,
+// types no instance reported are rejected.
+func RegisterTypes(names []string) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, name := range names {
+		if name != "" && !slices.Contains(registry.types, Type(name)) {
+			registry.types = append(registry.types, Type(name))
+		}
+	}
+	slices.Sort(registry.types)
+}
+
+// ResetTypesForTest empties the registry, so that tests registering types
+// leave it as they found it
+func ResetTypesForTest() {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.types = nil
+}
+
 // IsValidType checks if the given string is a valid entity type (case-insensitive)
 func IsValidType(t string) bool {
 	lower := strings.ToLower(t)
-	for _, vt := range ValidTypes {
+	for _, vt := range Types() {
 		if strings.ToLower(string(vt)) == lower {
 			return true
 		}
@@ -64,7 +106,7 @@ func IsValidType(t string) bool {
 // ParseType converts a string to a Type with case-insensitive matching
 func ParseType(t string) (Type, error) {
 	lower := strings.ToLower(t)
-	for _, vt := range ValidTypes {
+	for _, vt := range Types() {
 		if strings.ToLower(string(vt)) == lower {
 			return vt, nil
 		}
@@ -72,7 +114,71 @@ func ParseType(t string) (Type, error) {
 	return "", fmt.Errorf("invalid entity type: %s", t)
 }
 
-// Pluralize returns the plural form of the entity type
-func Pluralize(t Type) string {
-	return string(t) + "s"
+// resourceNames are the collection names TP gives entity types in its v1
+// API, for types whose name is not the regular English plural or which are
+// commonly referenced by other tools. Entries were checked against
+// /api/v1/Index/meta.
+var resourceNames = map[Type]string{
+	TypeUserStory:     "UserStories",
+	TypeBug:           "Bugs",
+	TypeTask:          "Tasks",
+	TypeFeature:       "Features",
+	TypeEpic:          "Epics",
+	TypePortfolioEpic: "PortfolioEpics",
+	TypeSolution:      "Solutions",
+	TypeRequest:       "Requests",
+	TypeImpediment:    "Impediments",
+	TypeTestCase:      "TestCases",
+	TypeTestPlan:      "TestPlans",
+	TypeProject:       "Projects",
+	TypeTeam:          "Teams",
+	TypeIteration:     "Iterations",
+	TypeTeamIteration: "TeamIterations",
+	TypeRelease:       "Releases",
+	TypeProgram:       "Programs",
+	"Process":         "Processes",
+	"Priority":        "Priorities",
+	"Severity":        "Severities",
+	"Company":         "Companies",
+	"Time":            "Times",
+	"TimeSheet":       "TimeSheets",
+	"EntityState":     "EntityStates",
+	"GeneralUser":     "GeneralUsers",
+	"User":            "Users",
+	"Requester":       "Requesters",
+	"Comment":         "Comments",
+	"Attachment":      "Attachments",
+	"Assignment":      "Assignments",
+	"Relation":        "Relations",
+	"CustomField":     "CustomFields",
+	"TestPlanRun":     "TestPlanRuns",
+	"TestCaseRun":     "TestCaseRuns",
+	"Build":           "Builds",
+	"Milestone":       "Milestones",
+	"Workflow":        "Workflows",
+	"Role":            "Roles",
+}
+
+// ResourceName returns the v1 API collection name of an entity type, such as
+// UserStories for UserStory, from the static table or, for types it does not
+// list, by English plural rules. Clients that have read TP's metadata should
+// prefer the names it gives.
+func ResourceName(t Type) string {
+	if name, ok := resourceNames[t]; ok {
+		return name
+	}
+	return pluralize(string(t))
+}
+
+// pluralize applies English plural rules to the last word of a type name
+func pluralize(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	}
+	return name + "s"
 }
@@ -80,28 +80,58 @@ func TestParseType(t *testing.T) {
 	}
 }
 
-func TestPluralize(t *testing.T) {
+func TestResourceName(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    Type
 		expected string
 	}{
-		{"UserStory plural", TypeUserStory, "UserStorys"},
-		{"Bug plural", TypeBug, "Bugs"},
-		{"Task plural", TypeTask, "Tasks"},
-		{"Feature plural", TypeFeature, "Features"},
-		{"Epic plural", TypeEpic, "Epics"},
-		{"PortfolioEpic plural", TypePortfolioEpic, "PortfolioEpics"},
-		{"TestCase plural", TypeTestCase, "TestCases"},
-		{"TeamIteration plural", TypeTeamIteration, "TeamIterations"},
+		{"UserStory", TypeUserStory, "UserStories"},
+		{"Bug", TypeBug, "Bugs"},
+		{"Task", TypeTask, "Tasks"},
+		{"Feature", TypeFeature, "Features"},
+		{"Epic", TypeEpic, "Epics"},
+		{"PortfolioEpic", TypePortfolioEpic, "PortfolioEpics"},
+		{"TestCase", TypeTestCase, "TestCases"},
+		{"TeamIteration", TypeTeamIteration, "TeamIterations"},
+		{"Process from the table", "Process", "Processes"},
+		{"Priority from the table", "Priority", "Priorities"},
+		{"unlisted type ending in consonant y", "Strategy", "Strategies"},
+		{"unlisted type ending in vowel y", "Survey", "Surveys"},
+		{"unlisted type ending in s", "Status", "Statuses"},
+		{"unlisted type ending in ch", "Branch", "Branches"},
+		{"unlisted regular type", "Objective", "Objectives"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := Pluralize(tt.input)
+			result := ResourceName(tt.input)
 			if result != tt.expected {
-				t.Errorf("Pluralize(%v) = %q; want %q", tt.input, result, tt.expected)
+				t.Errorf("ResourceName(%v) = %q; want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
 }
+
+func TestRegisterTypes(t *testing.T) {
+	t.Cleanup(ResetTypesForTest)
+
+	if _, err := ParseType("Objective"); err == nil {
+		t.Fatal("expected an unregistered custom type to be rejected")
+	}
+
+	RegisterTypes([]string{"UserStory", "Objective", "TimeSheet"})
+	got, err := ParseType("objective")
+	if err != nil || got != "Objective" {
+		t.Errorf("ParseType(objective) = %q, %v; want Objective", got, err)
+	}
+	// Once TP's types are known, standard types it did not report are rejected
+	if IsValidType("Bug") {
+		t.Error("expected a type no instance reported to be rejected")
+	}
+
+	RegisterTypes([]string{"Bug", "Objective"})
+	if types := Types(); len(types) != 4 {
+		t.Errorf("Types() = %v, want the union of registered types", types)
+	}
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorkflowState is a state of the workflow a process defines for an entity
+// type
+type WorkflowState struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	IsInitial bool   `json:"isInitial,omitempty"`
+	IsFinal   bool   `json:"isFinal,omitempty"`
+	// NextStates are the IDs of the states the workflow allows moving to
+	NextStates []int `json:"nextStates"`
+	// ParentID is set on the sub-states of team workflows, naming the
+	// process state they refine
+	ParentID int `json:"parentId,omitempty"`
+	// WorkflowID is the workflow the state belongs to, telling apart the
+	// team workflows of a process
+	WorkflowID int `json:"workflowId,omitempty"`
+}
+
+// FindState returns the process-level state named name, ignoring case.
+// Team workflow sub-states are not entity states, so naming one is an error
+// that points at its process state; see FindTeamState for those.
+func FindState(states []WorkflowState, name string) (WorkflowState, error) {
+	var names []string
+	for _, s := range states {
+		if s.ParentID == 0 {
+			if strings.EqualFold(s.Name, name) {
+				return s, nil
+			}
+			names = append(names, s.Name)
+		}
+	}
+	for _, s := range states {
+		if s.ParentID != 0 && strings.EqualFold(s.Name, name) {
+			parent, _ := stateByID(states, s.ParentID)
+			return WorkflowState{}, fmt.Errorf("%q is a team workflow state; the entity's own state is %q", s.Name, parent.Name)
+		}
+	}
+	list := strings.Join(names, ", ")
+	if suggestion := closest(name, names); suggestion != "" {
+		return WorkflowState{}, fmt.Errorf("no state named %q in this workflow (states: %s); did you mean %q?", name, list, suggestion)
+	}
+	return WorkflowState{}, fmt.Errorf("no state named %q in this workflow (states: %s)", name, list)
+}
+
+// FindTeamState returns the state named name, ignoring case, of the team
+// workflow with the given ID, and false when that workflow has none
+func FindTeamState(states []WorkflowState, workflowID int, name string) (WorkflowState, bool) {
+	if workflowID == 0 {
+		return WorkflowState{}, false
+	}
+	for _, s := range states {
+		if s.ParentID != 0 && s.WorkflowID == workflowID && strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return WorkflowState{}, false
+}
+
+// CheckTransition returns an error unless the workflow allows moving from
+// the state with ID fromID to to. A current state missing from states
+// cannot be checked and is let through.
+func CheckTransition(states []WorkflowState, fromID int, to WorkflowState) error {
+	from, ok := stateByID(states, fromID)
+	if !ok {
+		return nil
+	}
+	if from.ID == to.ID {
+		return fmt.Errorf("already in state %q", from.Name)
+	}
+	var allowed []string
+	for _, id := range from.NextStates {
+		if id == to.ID {
+			return nil
+		}
+		if next, ok := stateByID(states, id); ok {
+			allowed = append(allowed, next.Name)
+		}
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("the workflow allows no transitions from %q", from.Name)
+	}
+	return fmt.Errorf("the workflow does not allow moving from %q to %q (allowed: %s)", from.Name, to.Name, strings.Join(allowed, ", "))
+}
+
+func stateByID(states []WorkflowState, id int) (WorkflowState, bool) {
+	for _, s := range states {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return WorkflowState{}, false
+}
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func testWorkflow() []WorkflowState {
+	return []WorkflowState{
+		{ID: 1, Name: "Open", IsInitial: true, NextStates: []int{2}},
+		{ID: 2, Name: "In Progress", NextStates: []int{1, 3}},
+		{ID: 3, Name: "Done", IsFinal: true, NextStates: []int{}},
+		{ID: 20, Name: "Code Review", ParentID: 2, WorkflowID: 9, NextStates: []int{21}},
+		{ID: 21, Name: "Testing", ParentID: 2, WorkflowID: 9, NextStates: []int{}},
+	}
+}
+
+func TestFindTeamState(t *testing.T) {
+	if got, ok := FindTeamState(testWorkflow(), 9, "code review"); !ok || got.ID != 20 {
+		t.Errorf("FindTeamState = %+v, %v; want state 20", got, ok)
+	}
+	if _, ok := FindTeamState(testWorkflow(), 9, "In Progress"); ok {
+		t.Error("expected a process state not to be a team state")
+	}
+	if _, ok := FindTeamState(testWorkflow(), 8, "Code Review"); ok {
+		t.Error("expected another team workflow's state not to match")
+	}
+}
+
+func TestFindState(t *testing.T) {
+	got, err := FindState(testWorkflow(), "in progress")
+	if err != nil || got.ID != 2 {
+		t.Errorf("FindState = %+v, %v; want state 2", got, err)
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{"near miss", "In Progres", `did you mean "In Progress"?`},
+		{"unknown", "Blocked", "states: Open, In Progress, Done"},
+		{"team sub-state", "code review", `"Code Review" is a team workflow state; the entity's own state is "In Progress"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FindState(testWorkflow(), tt.want)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	states := testWorkflow()
+	tests := []struct {
+		name    string
+		from    int
+		to      int
+		wantErr string
+	}{
+		{"same state", 1, 1, "already in state"},
+		{"next state", 1, 2, ""},
+		{"not allowed", 1, 3, `does not allow moving from "Open" to "Done" (allowed: In Progress)`},
+		{"final state", 3, 1, `allows no transitions from "Done"`},
+		{"unknown current state", 99, 3, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to, _ := stateByID(states, tt.to)
+			err := CheckTransition(states, tt.from, to)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
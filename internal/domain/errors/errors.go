@@ -1,9 +1,11 @@
 package errors
 
 import (
+	"encoding/json"
 	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // InvalidEntityTypeError is returned when an invalid entity type is provided
@@ -28,9 +30,10 @@ func (e *ValidationError) Error() string {
 // APIError is returned when the TP API returns a non-success response
 type APIError struct {
 	StatusCode int
-	Message    string // Parsed/clean error message
-	RawBody    string // Full response body (with token masked)
-	Context    string // Method + URL
+	Message    string        // Parsed/clean error message
+	RawBody    string        // Full response body (with token masked)
+	Context    string        // Method + URL
+	RetryAfter time.Duration // Wait requested by a Retry-After header, if any
 }
 
 func (e *APIError) Error() string {
@@ -40,11 +43,142 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
 }
 
+// NotFoundError is a 404 response: the entity or resource does not exist
+type NotFoundError struct{ *APIError }
+
+func (e *NotFoundError) Unwrap() error { return e.APIError }
+
+// PermissionError is a 401 or 403 response: TP rejected the credentials, or
+// they do not grant access to the resource
+type PermissionError struct{ *APIError }
+
+func (e *PermissionError) Unwrap() error { return e.APIError }
+
+// Unauthenticated reports whether TP rejected the credentials themselves
+// rather than their access to the resource
+func (e *PermissionError) Unauthenticated() bool {
+	return e.StatusCode == 401
+}
+
+// RateLimitError is a 429 response; RetryAfter holds the wait TP asked for
+type RateLimitError struct{ *APIError }
+
+func (e *RateLimitError) Unwrap() error { return e.APIError }
+
+// ConflictError is a 409 response: the change conflicts with the entity's
+// current state
+type ConflictError struct{ *APIError }
+
+func (e *ConflictError) Unwrap() error { return e.APIError }
+
+// ValidationFailedError is a 400 or 422 response: TP rejected the request's
+// query or payload
+type ValidationFailedError struct {
+	*APIError
+	// Fields names the fields TP reported as invalid, if it named any
+	Fields []string
+}
+
+func (e *ValidationFailedError) Unwrap() error { return e.APIError }
+
+// Classify returns the typed error for e's status code, or e itself when no
+// type applies. Each typed error unwraps to e, so callers that only need the
+// status code can keep using errors.As with *APIError.
+func Classify(e *APIError) error {
+	switch e.StatusCode {
+	case 400, 422:
+		return &ValidationFailedError{APIError: e, Fields: parseErrorFields(e.RawBody)}
+	case 401, 403:
+		return &PermissionError{APIError: e}
+	case 404:
+		return &NotFoundError{APIError: e}
+	case 409:
+		return &ConflictError{APIError: e}
+	case 429:
+		return &RateLimitError{APIError: e}
+	}
+	return e
+}
+
+// jsonErrorBody is the error body TP returns for format=json requests. Field
+// failures are listed in Details.Items or, from some endpoints, in Errors.
+type jsonErrorBody struct {
+	Message string `json:"Message"`
+	Details struct {
+		Items []jsonFieldError `json:"Items"`
+	} `json:"Details"`
+	Errors []jsonFieldError `json:"Errors"`
+}
+
+type jsonFieldError struct {
+	Message      string `json:"Message"`
+	PropertyName string `json:"PropertyName"`
+	Field        string `json:"Field"`
+}
+
+func (f jsonFieldError) name() string {
+	if f.PropertyName != "" {
+		return f.PropertyName
+	}
+	return f.Field
+}
+
+// parseJSONErrorBody decodes body if it is a JSON error body
+func parseJSONErrorBody(body string) (jsonErrorBody, bool) {
+	var parsed jsonErrorBody
+	trimmed := strings.TrimSpace(body)
+	if !strings.HasPrefix(trimmed, "{") || json.Unmarshal([]byte(trimmed), &parsed) != nil {
+		return parsed, false
+	}
+	return parsed, true
+}
+
+func (b jsonErrorBody) fieldErrors() []jsonFieldError {
+	return append(b.Details.Items, b.Errors...)
+}
+
+// parseErrorFields returns the names of the fields a TP error body reports as invalid
+func parseErrorFields(body string) []string {
+	parsed, ok := parseJSONErrorBody(body)
+	if !ok {
+		return nil
+	}
+	var fields []string
+	for _, f := range parsed.fieldErrors() {
+		if name := f.name(); name != "" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
 // ParseTPErrorBody extracts a human-readable message from a TP API error response.
 // The TP API returns XML error bodies like:
 //
 //	<Error><Status>BadRequest</Status><Message>Error during parameters parsing.</Message>...</Error>
+//
+// and, for format=json requests, JSON bodies like:
+//
+//	{"Status":"BadRequest","Message":"...","Details":{"Items":[{"PropertyName":"Name","Message":"..."}]}}
+//
+// Field failures are appended to the message.
 func ParseTPErrorBody(body string) string {
+	if parsed, ok := parseJSONErrorBody(body); ok && (parsed.Message != "" || len(parsed.fieldErrors()) > 0) {
+		parts := []string{}
+		if parsed.Message != "" {
+			parts = append(parts, parsed.Message)
+		}
+		for _, f := range parsed.fieldErrors() {
+			switch {
+			case f.name() != "" && f.Message != "":
+				parts = append(parts, f.name()+": "+f.Message)
+			case f.Message != "":
+				parts = append(parts, f.Message)
+			}
+		}
+		return strings.Join(parts, "; ")
+	}
+
 	start := strings.Index(body, "<Message>")
 	end := strings.Index(body, "</Message>")
 	if start != -1 && end != -1 && end > start {
@@ -68,11 +202,37 @@ func (e *SSRFError) Error() string {
 	return fmt.Sprintf("SSRF validation failed for URL %s: %s", e.URL, e.Reason)
 }
 
-// IsRetryable returns whether the error should be retried
+// CircuitOpenError is returned without contacting TP while the circuit
+// breaker for an instance is open after repeated failures
+type CircuitOpenError struct {
+	Domain  string
+	RetryIn time.Duration // Time until the breaker lets a probe request through
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.RetryIn <= 0 {
+		return fmt.Sprintf("circuit breaker for %s is waiting for a probe request to succeed", e.Domain)
+	}
+	return fmt.Sprintf("circuit breaker open for %s after repeated failures; next attempt allowed in %s", e.Domain, e.RetryIn.Round(time.Second))
+}
+
+// IsRetryable returns whether the error should be retried. An open circuit
+// breaker and 400/401/403/404/409/422 responses are final: repeating the
+// same request cannot change the outcome. Whether the caller gave up cannot
+// be told from err, since a request that hit http.Client.Timeout matches
+// context.DeadlineExceeded too; callers check their own context instead.
 func IsRetryable(err error) bool {
+	var openErr *CircuitOpenError
+	if stderrors.As(err, &openErr) {
+		return false
+	}
 	var apiErr *APIError
 	if stderrors.As(err, &apiErr) {
-		return apiErr.StatusCode != 400 && apiErr.StatusCode != 401
+		switch apiErr.StatusCode {
+		case 400, 401, 403, 404, 409, 422:
+			return false
+		}
+		return true
 	}
 	return true // non-API errors are retryable by default
 }
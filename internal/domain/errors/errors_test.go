@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
 	"testing"
 )
@@ -63,6 +65,36 @@ func TestIsRetryable(t *testing.T) {
 			err:      &APIError{StatusCode: 401},
 			expected: false,
 		},
+		{
+			name:     "403 not retryable",
+			err:      &APIError{StatusCode: 403},
+			expected: false,
+		},
+		{
+			name:     "404 not retryable",
+			err:      &APIError{StatusCode: 404},
+			expected: false,
+		},
+		{
+			name:     "409 not retryable",
+			err:      &APIError{StatusCode: 409},
+			expected: false,
+		},
+		{
+			name:     "422 not retryable",
+			err:      &APIError{StatusCode: 422},
+			expected: false,
+		},
+		{
+			name:     "429 retryable",
+			err:      &APIError{StatusCode: 429},
+			expected: true,
+		},
+		{
+			name:     "open circuit not retryable",
+			err:      &CircuitOpenError{Domain: "x.tpondemand.com"},
+			expected: false,
+		},
 		{
 			name:     "500 retryable",
 			err:      &APIError{StatusCode: 500},
@@ -73,6 +105,11 @@ func TestIsRetryable(t *testing.T) {
 			err:      &ValidationError{Field: "test", Message: "error"},
 			expected: true,
 		},
+		{
+			name:     "client timeout retryable",
+			err:      fmt.Errorf("Get \"https://example\": %w", context.DeadlineExceeded),
+			expected: true,
+		},
 		{
 			name:     "wrapped 400 not retryable",
 			err:      fmt.Errorf("wrapped: %w", &APIError{StatusCode: 400}),
@@ -121,6 +158,16 @@ func TestParseTPErrorBody(t *testing.T) {
 			body:     `{"error": "something went wrong"}`,
 			expected: `{"error": "something went wrong"}`,
 		},
+		{
+			name:     "extracts message from TP JSON error",
+			body:     `{"Status":"BadRequest","Message":"Error during parameters parsing.","Type":"Presentational"}`,
+			expected: "Error during parameters parsing.",
+		},
+		{
+			name:     "appends field failures from TP JSON error",
+			body:     `{"Status":"BadRequest","Message":"Validation failed","Details":{"Items":[{"PropertyName":"Name","Message":"Name should be specified"},{"Message":"Project is required"}]}}`,
+			expected: "Validation failed; Name: Name should be specified; Project is required",
+		},
 		{
 			name:     "returns empty response message for empty body",
 			body:     "",
@@ -199,3 +246,50 @@ func TestMaskToken(t *testing.T) {
 		})
 	}
 }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		status int
+		check  func(error) bool
+	}{
+		{400, func(err error) bool { var e *ValidationFailedError; return stderrors.As(err, &e) }},
+		{401, func(err error) bool { var e *PermissionError; return stderrors.As(err, &e) && e.Unauthenticated() }},
+		{403, func(err error) bool { var e *PermissionError; return stderrors.As(err, &e) && !e.Unauthenticated() }},
+		{404, func(err error) bool { var e *NotFoundError; return stderrors.As(err, &e) }},
+		{409, func(err error) bool { var e *ConflictError; return stderrors.As(err, &e) }},
+		{429, func(err error) bool { var e *RateLimitError; return stderrors.As(err, &e) }},
+		{500, func(err error) bool { _, ok := stderrors.Unwrap(err).(*APIError); return ok }},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			apiErr := &APIError{StatusCode: tt.status, Message: "failed"}
+			err := fmt.Errorf("wrapped: %w", Classify(apiErr))
+			if !tt.check(err) {
+				t.Errorf("unexpected type %T for status %d", stderrors.Unwrap(err), tt.status)
+			}
+			// Typed errors still expose the underlying APIError
+			var unwrapped *APIError
+			if !stderrors.As(err, &unwrapped) || unwrapped != apiErr {
+				t.Error("expected the typed error to unwrap to the APIError")
+			}
+			if err.Error() != "wrapped: "+apiErr.Error() {
+				t.Errorf("expected the APIError message, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestClassify_ValidationFields(t *testing.T) {
+	err := Classify(&APIError{
+		StatusCode: 400,
+		RawBody:    `{"Message":"Validation failed","Errors":[{"Field":"Effort","Message":"must be positive"}],"Details":{"Items":[{"PropertyName":"Name","Message":"required"}]}}`,
+	})
+	var validationErr *ValidationFailedError
+	if !stderrors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationFailedError, got %T", err)
+	}
+	if len(validationErr.Fields) != 2 || validationErr.Fields[0] != "Name" || validationErr.Fields[1] != "Effort" {
+		t.Errorf("Fields = %v, want [Name Effort]", validationErr.Fields)
+	}
+}
@@ -0,0 +1,19 @@
+package query
+
+import "tp-mcp-go/internal/domain/entity"
+
+// V2Request is a query against the v2 API, whose select shapes each item
+// with projections, nested collections and aggregates such as count
+type V2Request struct {
+	EntityType entity.Type
+	// Select is a v2 projection, e.g. {id,name,state:entityState.name}
+	Select string
+	// Where is a v2 expression, e.g. entityState.isFinal==false
+	Where string
+	// OrderBy is a v2 sort, e.g. "createDate desc"
+	OrderBy string
+	Take    int
+	Skip    int
+	// Cursor is the next-page URL of a previous response; it replaces every other field
+	Cursor string
+}
@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"context"
+	"fmt"
 	"tp-mcp-go/internal/client"
 	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/domain/query"
@@ -10,9 +11,19 @@ import (
 // MockClient satisfies client.Client with configurable function fields
 type MockClient struct {
 	SearchEntitiesFn        func(ctx context.Context, req query.SearchRequest) (*query.PaginatedResponse, error)
+	QueryV2Fn               func(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error)
 	GetEntityFn             func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error)
 	CreateEntityFn          func(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error)
 	UpdateEntityFn          func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error)
+	PreviewCreateEntityFn   func(ctx context.Context, entityType entity.Type, data map[string]any) (*client.WriteRequest, error)
+	PreviewUpdateEntityFn   func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (*client.WriteRequest, error)
+	DeleteEntityFn          func(ctx context.Context, entityType entity.Type, id int) error
+	PreviewDeleteEntityFn   func(ctx context.Context, entityType entity.Type, id int) (*client.WriteRequest, error)
+	BulkCreateFn            func(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error)
+	BulkUpdateFn            func(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error)
+	PreviewBulkCreateFn     func(ctx context.Context, entityType entity.Type, items []map[string]any) (*client.WriteRequest, error)
+	PreviewBulkUpdateFn     func(ctx context.Context, entityType entity.Type, items []map[string]any) (*client.WriteRequest, error)
+	PreviewCreateCommentFn  func(ctx context.Context, entityID int, description string) (*client.WriteRequest, error)
 	CreateCommentFn         func(ctx context.Context, entityID int, description string) (*entity.Comment, error)
 	ListCommentsFn          func(ctx context.Context, entityID int, take int, include []string) ([]entity.Comment, error)
 	ListAttachmentsFn       func(ctx context.Context, entityID int, take int) ([]entity.Attachment, error)
@@ -20,6 +31,8 @@ type MockClient struct {
 	DownloadAttachmentFn    func(ctx context.Context, uri string) ([]byte, string, error)
 	FetchMetadataFn         func(ctx context.Context) (any, error)
 	GetValidEntityTypesFn   func(ctx context.Context) ([]string, error)
+	GetCustomFieldsFn       func(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error)
+	GetEntityStatesFn       func(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error)
 	InitializeCacheFn       func(ctx context.Context) error
 }
 
@@ -33,6 +46,13 @@ func (m *MockClient) SearchEntities(ctx context.Context, req query.SearchRequest
 	return &query.PaginatedResponse{}, nil
 }
 
+func (m *MockClient) QueryV2(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error) {
+	if m.QueryV2Fn != nil {
+		return m.QueryV2Fn(ctx, req)
+	}
+	return &query.PaginatedResponse{}, nil
+}
+
 func (m *MockClient) GetEntity(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
 	if m.GetEntityFn != nil {
 		return m.GetEntityFn(ctx, entityType, id, include)
@@ -54,6 +74,70 @@ func (m *MockClient) UpdateEntity(ctx context.Context, entityType entity.Type, i
 	return map[string]any{}, nil
 }
 
+func (m *MockClient) PreviewCreateEntity(ctx context.Context, entityType entity.Type, data map[string]any) (*client.WriteRequest, error) {
+	if m.PreviewCreateEntityFn != nil {
+		return m.PreviewCreateEntityFn(ctx, entityType, data)
+	}
+	return &client.WriteRequest{Method: "POST", URL: "https://test.tpondemand.com/api/v1/" + entity.ResourceName(entityType), Body: data}, nil
+}
+
+func (m *MockClient) PreviewUpdateEntity(ctx context.Context, entityType entity.Type, id int, data map[string]any) (*client.WriteRequest, error) {
+	if m.PreviewUpdateEntityFn != nil {
+		return m.PreviewUpdateEntityFn(ctx, entityType, id, data)
+	}
+	return &client.WriteRequest{Method: "POST", URL: fmt.Sprintf("https://test.tpondemand.com/api/v1/%s/%d", entity.ResourceName(entityType), id), Body: data}, nil
+}
+
+func (m *MockClient) DeleteEntity(ctx context.Context, entityType entity.Type, id int) error {
+	if m.DeleteEntityFn != nil {
+		return m.DeleteEntityFn(ctx, entityType, id)
+	}
+	return nil
+}
+
+func (m *MockClient) PreviewDeleteEntity(ctx context.Context, entityType entity.Type, id int) (*client.WriteRequest, error) {
+	if m.PreviewDeleteEntityFn != nil {
+		return m.PreviewDeleteEntityFn(ctx, entityType, id)
+	}
+	return &client.WriteRequest{Method: "DELETE", URL: fmt.Sprintf("https://test.tpondemand.com/api/v1/%s/%d", entity.ResourceName(entityType), id)}, nil
+}
+
+func (m *MockClient) BulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+	if m.BulkCreateFn != nil {
+		return m.BulkCreateFn(ctx, entityType, items)
+	}
+	return []client.BulkResult{}, nil
+}
+
+func (m *MockClient) BulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+	if m.BulkUpdateFn != nil {
+		return m.BulkUpdateFn(ctx, entityType, items)
+	}
+	return []client.BulkResult{}, nil
+}
+
+func (m *MockClient) PreviewBulkCreate(ctx context.Context, entityType entity.Type, items []map[string]any) (*client.WriteRequest, error) {
+	if m.PreviewBulkCreateFn != nil {
+		return m.PreviewBulkCreateFn(ctx, entityType, items)
+	}
+	return &client.WriteRequest{Method: "POST", URL: fmt.Sprintf("https://test.tpondemand.com/api/v1/%s/bulk", entity.ResourceName(entityType)), Items: items}, nil
+}
+
+func (m *MockClient) PreviewBulkUpdate(ctx context.Context, entityType entity.Type, items []map[string]any) (*client.WriteRequest, error) {
+	if m.PreviewBulkUpdateFn != nil {
+		return m.PreviewBulkUpdateFn(ctx, entityType, items)
+	}
+	return &client.WriteRequest{Method: "POST", URL: fmt.Sprintf("https://test.tpondemand.com/api/v1/%s/bulk", entity.ResourceName(entityType)), Items: items}, nil
+}
+
+func (m *MockClient) PreviewCreateComment(ctx context.Context, entityID int, description string) (*client.WriteRequest, error) {
+	if m.PreviewCreateCommentFn != nil {
+		return m.PreviewCreateCommentFn(ctx, entityID, description)
+	}
+	body := map[string]any{"Description": description, "General": map[string]any{"Id": entityID}, "IsPrivate": true}
+	return &client.WriteRequest{Method: "POST", URL: "https://test.tpondemand.com/api/v1/Comments", Body: body}, nil
+}
+
 func (m *MockClient) CreateComment(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
 	if m.CreateCommentFn != nil {
 		return m.CreateCommentFn(ctx, entityID, description)
@@ -103,6 +187,20 @@ func (m *MockClient) GetValidEntityTypes(ctx context.Context) ([]string, error)
 	return []string{}, nil
 }
 
+func (m *MockClient) GetCustomFields(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+	if m.GetCustomFieldsFn != nil {
+		return m.GetCustomFieldsFn(ctx, entityType)
+	}
+	return nil, nil
+}
+
+func (m *MockClient) GetEntityStates(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error) {
+	if m.GetEntityStatesFn != nil {
+		return m.GetEntityStatesFn(ctx, entityType, processID)
+	}
+	return nil, nil
+}
+
 func (m *MockClient) InitializeCache(ctx context.Context) error {
 	if m.InitializeCacheFn != nil {
 		return m.InitializeCacheFn(ctx)
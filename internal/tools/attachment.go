@@ -7,14 +7,15 @@ import (
 	"strings"
 
 	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
 
 	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
 	"github.com/strowk/foxy-contexts/pkg/mcp"
 )
 
 // NewListAttachmentsTool creates a tool to list attachments for an entity
-func NewListAttachmentsTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewListAttachmentsTool(c client.Client, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "list_attachments",
 			Description: ptr("List attachments for a Target Process entity. " +
@@ -26,29 +27,23 @@ func NewListAttachmentsTool(c client.Client) fxctx.Tool {
 						"type":        "integer",
 						"description": "Entity ID to list attachments for",
 					},
-					"take": {
-						"type":        "integer",
-						"description": "Number of attachments to return (default: 100)",
-					},
+					"take":    takeSchema("attachments", limits.AttachmentsTake),
+					"noCache": noCacheSchema(),
 				},
 				Required: []string{"entityId"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse entityId
 			entityID, err := getIntArg(args, "entityId")
 			if err != nil {
 				return errorResult(err)
 			}
 
-			// Parse take with default
-			take := 100 // default
-			if t, err := getIntArg(args, "take"); err == nil {
-				take = t
-			}
+			take := getTakeArg(args, limits.AttachmentsTake)
 
 			// Call client
-			attachments, err := c.ListAttachments(context.Background(), entityID, take)
+			attachments, err := c.ListAttachments(ctx, entityID, take)
 			if err != nil {
 				return errorResult(err)
 			}
@@ -58,16 +53,25 @@ func NewListAttachmentsTool(c client.Client) fxctx.Tool {
 	)
 }
 
+// formatBytes renders a size limit, in MB when it is a whole number of MB
+func formatBytes(n int64) string {
+	const mb = 1024 * 1024
+	if n%mb == 0 {
+		return fmt.Sprintf("%dMB", n/mb)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
 // NewDownloadAttachmentTool creates a tool to download an attachment
-func NewDownloadAttachmentTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewDownloadAttachmentTool(c client.Client, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "download_attachment",
 			Description: ptr("Download a Target Process attachment by ID. " +
 				"Returns the attachment content with appropriate encoding based on MIME type. " +
 				"Images are returned as base64-encoded image content, text files as plain text, " +
 				"and other types as base64 with a note. " +
-				"Maximum file size: 50MB."),
+				fmt.Sprintf("Maximum file size: %s.", formatBytes(limits.MaxAttachmentSize))),
 			InputSchema: mcp.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]map[string]interface{}{
@@ -79,7 +83,7 @@ func NewDownloadAttachmentTool(c client.Client) fxctx.Tool {
 				Required: []string{"attachmentId"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse attachmentId
 			attachmentID, err := getIntArg(args, "attachmentId")
 			if err != nil {
@@ -87,20 +91,19 @@ func NewDownloadAttachmentTool(c client.Client) fxctx.Tool {
 			}
 
 			// Get attachment metadata
-			attachment, err := c.GetAttachmentMetadata(context.Background(), attachmentID)
+			attachment, err := c.GetAttachmentMetadata(ctx, attachmentID)
 			if err != nil {
 				return errorResult(err)
 			}
 
-			// Check size limit (50MB)
-			const maxSize = 50 * 1024 * 1024
-			if attachment.Size > maxSize {
-				return errorResult(fmt.Errorf("attachment size (%d bytes) exceeds maximum allowed size (50MB)", attachment.Size))
+			// Check size limit
+			if int64(attachment.Size) > limits.MaxAttachmentSize {
+				return errorResult(fmt.Errorf("attachment size (%d bytes) exceeds maximum allowed size (%s)", attachment.Size, formatBytes(limits.MaxAttachmentSize)))
 			}
 
 			// Download attachment
 			downloadUri := fmt.Sprintf("/Attachment.aspx?AttachmentID=%d", attachmentID)
-		data, mimeType, err := c.DownloadAttachment(context.Background(), downloadUri)
+			data, mimeType, err := c.DownloadAttachment(ctx, downloadUri)
 			if err != nil {
 				return errorResult(err)
 			}
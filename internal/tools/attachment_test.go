@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/testutil"
 
@@ -27,7 +28,7 @@ func TestListAttachments(t *testing.T) {
 		},
 	}
 
-	tool := NewListAttachmentsTool(mockClient)
+	tool := NewListAttachmentsTool(mockClient, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"entityId": float64(300),
 	})
@@ -56,7 +57,7 @@ func TestDownloadAttachment_Image(t *testing.T) {
 		},
 	}
 
-	tool := NewDownloadAttachmentTool(mockClient)
+	tool := NewDownloadAttachmentTool(mockClient, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"attachmentId": float64(1),
 	})
@@ -95,7 +96,7 @@ func TestDownloadAttachment_Text(t *testing.T) {
 		},
 	}
 
-	tool := NewDownloadAttachmentTool(mockClient)
+	tool := NewDownloadAttachmentTool(mockClient, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"attachmentId": float64(2),
 	})
@@ -130,7 +131,7 @@ func TestDownloadAttachment_SizeGuard(t *testing.T) {
 		},
 	}
 
-	tool := NewDownloadAttachmentTool(mockClient)
+	tool := NewDownloadAttachmentTool(mockClient, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"attachmentId": float64(3),
 	})
@@ -146,3 +147,26 @@ func TestDownloadAttachment_SizeGuard(t *testing.T) {
 	}
 	assert.Contains(t, textContent.Text, fmt.Sprintf("Error: attachment size (%d bytes) exceeds maximum allowed size (50MB)", largeSize))
 }
+
+func TestDownloadAttachment_ConfiguredSizeLimit(t *testing.T) {
+	mockClient := &testutil.MockClient{
+		GetAttachmentMetadataFn: func(ctx context.Context, attachmentID int) (*entity.Attachment, error) {
+			return &entity.Attachment{ID: 4, Name: "medium.bin", Size: 2 * 1024 * 1024}, nil
+		},
+	}
+
+	limits := config.DefaultLimits()
+	limits.MaxAttachmentSize = 1024 * 1024
+	tool := NewDownloadAttachmentTool(mockClient, limits)
+	result := tool.Callback(map[string]interface{}{
+		"attachmentId": float64(4),
+	})
+
+	assert.NotNil(t, result.IsError)
+	assert.True(t, *result.IsError)
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("Expected content to be mcp.TextContent, got %T", result.Content[0])
+	}
+	assert.Contains(t, textContent.Text, "exceeds maximum allowed size (1MB)")
+}
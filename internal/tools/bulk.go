@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// bulkSummary is the result of a bulk write tool
+type bulkSummary struct {
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []client.BulkResult `json:"results"`
+}
+
+// NewBulkCreateEntitiesTool creates a tool to create many entities in one request
+func NewBulkCreateEntitiesTool(c client.Client, writes config.WritesConfig, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
+		&mcp.Tool{
+			Name: "bulk_create_entities",
+			Description: ptr("Create many Target Process entities of one type in a single request. " +
+				"Each item is an object of entity fields and must include Name. " +
+				"Returns a success or error for every item, in request order."),
+			InputSchema: bulkSchema("Entity type to create (e.g., UserStory, Bug, Task, Feature)",
+				"Entities to create, each an object of fields such as {\"Name\": \"...\", \"Project\": {\"Id\": 123}}",
+				limits),
+		},
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			entityType, items, err := parseBulkArgs(args, limits)
+			if err != nil {
+				return errorResult(err)
+			}
+			for i, item := range items {
+				if name, _ := item["Name"].(string); name == "" {
+					return errorResult(fmt.Errorf("item %d: Name is required", i))
+				}
+			}
+
+			if isDryRun(args, writes) {
+				req, err := c.PreviewBulkCreate(ctx, entityType, items)
+				if err != nil {
+					return errorResult(err)
+				}
+				return dryRunResult(req, nil)
+			}
+
+			results, err := c.BulkCreate(ctx, entityType, items)
+			if err != nil {
+				return errorResult(err)
+			}
+			return jsonResult(summarizeBulk(results))
+		},
+	)
+}
+
+// NewBulkUpdateEntitiesTool creates a tool to update many entities in one request
+func NewBulkUpdateEntitiesTool(c client.Client, writes config.WritesConfig, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
+		&mcp.Tool{
+			Name: "bulk_update_entities",
+			Description: ptr("Update many Target Process entities of one type in a single request. " +
+				"Each item is an object with the entity's Id and the fields to change. " +
+				"Returns a success or error for every item, in request order."),
+			InputSchema: bulkSchema("Entity type (e.g., UserStory, Bug, Task, Feature)",
+				"Entities to update, each an object such as {\"Id\": 42, \"Name\": \"New Name\"}",
+				limits),
+		},
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			entityType, items, err := parseBulkArgs(args, limits)
+			if err != nil {
+				return errorResult(err)
+			}
+			for i, item := range items {
+				if _, err := getIntArg(item, "Id"); err != nil {
+					return errorResult(fmt.Errorf("item %d: %w", i, err))
+				}
+			}
+
+			if isDryRun(args, writes) {
+				req, err := c.PreviewBulkUpdate(ctx, entityType, items)
+				if err != nil {
+					return errorResult(err)
+				}
+				return dryRunResult(req, nil)
+			}
+
+			results, err := c.BulkUpdate(ctx, entityType, items)
+			if err != nil {
+				return errorResult(err)
+			}
+			return jsonResult(summarizeBulk(results))
+		},
+	)
+}
+
+// bulkSchema is the input schema shared by the bulk write tools
+func bulkSchema(typeDescription, itemsDescription string, limits config.LimitsConfig) mcp.ToolInputSchema {
+	return mcp.ToolInputSchema{
+		Type: "object",
+		Properties: map[string]map[string]interface{}{
+			"type": {
+				"type":        "string",
+				"description": typeDescription,
+				"enum":        entityTypeStrings(),
+			},
+			"items": {
+				"type":        "array",
+				"description": fmt.Sprintf("%s (at most %d)", itemsDescription, limits.MaxBatchSize),
+				"items":       map[string]interface{}{"type": "object"},
+				"minItems":    1,
+				"maxItems":    limits.MaxBatchSize,
+			},
+			"dryRun": dryRunSchema(),
+		},
+		Required: []string{"type", "items"},
+	}
+}
+
+// parseBulkArgs reads the type and items of a bulk write, enforcing the
+// maximum batch size
+func parseBulkArgs(args map[string]interface{}, limits config.LimitsConfig) (entity.Type, []map[string]any, error) {
+	typeStr := getStringArg(args, "type")
+	if typeStr == "" {
+		return "", nil, fmt.Errorf("type parameter is required")
+	}
+	entityType, err := entity.ParseType(typeStr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	raw, ok := getAnyArg(args, "items").([]any)
+	if !ok || len(raw) == 0 {
+		return "", nil, fmt.Errorf("items must be a non-empty array of objects")
+	}
+	if len(raw) > limits.MaxBatchSize {
+		return "", nil, fmt.Errorf("%d items exceeds the maximum batch size of %d; split them into smaller batches", len(raw), limits.MaxBatchSize)
+	}
+	items := make([]map[string]any, len(raw))
+	for i, v := range raw {
+		item, ok := v.(map[string]any)
+		if !ok {
+			return "", nil, fmt.Errorf("item %d must be an object", i)
+		}
+		items[i] = item
+	}
+	return entityType, items, nil
+}
+
+// summarizeBulk counts the successes and failures of a bulk write
+func summarizeBulk(results []client.BulkResult) bulkSummary {
+	s := bulkSummary{Results: results}
+	for _, r := range results {
+		if r.Success {
+			s.Succeeded++
+		} else {
+			s.Failed++
+		}
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/testutil"
+
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+func TestBulkCreateEntitiesTool_ReportsEachItem(t *testing.T) {
+	mock := &testutil.MockClient{
+		BulkCreateFn: func(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+			if entityType != entity.TypeTask || len(items) != 2 {
+				t.Errorf("unexpected call: %s %v", entityType, items)
+			}
+			return []client.BulkResult{
+				{Index: 0, Success: true, Entity: map[string]any{"Id": float64(11)}},
+				{Index: 1, Error: "Name is too long"},
+			}, nil
+		},
+	}
+
+	tool := NewBulkCreateEntitiesTool(mock, config.WritesConfig{}, config.DefaultLimits())
+	result := tool.Callback(map[string]interface{}{
+		"type":  "Task",
+		"items": []any{map[string]any{"Name": "First"}, map[string]any{"Name": "Second"}},
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var summary bulkSummary
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &summary); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if summary.Succeeded != 1 || summary.Failed != 1 || summary.Results[1].Error != "Name is too long" {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestBulkCreateEntitiesTool_EnforcesMaxBatchSize(t *testing.T) {
+	called := false
+	mock := &testutil.MockClient{
+		BulkCreateFn: func(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+			called = true
+			return nil, nil
+		},
+	}
+
+	limits := config.DefaultLimits()
+	limits.MaxBatchSize = 2
+	tool := NewBulkCreateEntitiesTool(mock, config.WritesConfig{}, limits)
+	result := tool.Callback(map[string]interface{}{
+		"type":  "Task",
+		"items": []any{map[string]any{"Name": "A"}, map[string]any{"Name": "B"}, map[string]any{"Name": "C"}},
+	})
+
+	if result.IsError == nil || !*result.IsError {
+		t.Fatal("expected an error for an oversized batch")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "maximum batch size of 2") {
+		t.Errorf("unexpected error: %s", text)
+	}
+	if called {
+		t.Error("expected the client not to be called")
+	}
+}
+
+func TestBulkUpdateEntitiesTool_RequiresIds(t *testing.T) {
+	tool := NewBulkUpdateEntitiesTool(&testutil.MockClient{}, config.WritesConfig{}, config.DefaultLimits())
+	result := tool.Callback(map[string]interface{}{
+		"type":  "Bug",
+		"items": []any{map[string]any{"Id": float64(1), "Name": "A"}, map[string]any{"Name": "B"}},
+	})
+
+	if result.IsError == nil || !*result.IsError {
+		t.Fatal("expected an error for an item without Id")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "item 1") {
+		t.Errorf("expected the error to name the item, got: %s", text)
+	}
+}
+
+func TestBulkUpdateEntitiesTool_DryRun(t *testing.T) {
+	mock := &testutil.MockClient{
+		BulkUpdateFn: func(ctx context.Context, entityType entity.Type, items []map[string]any) ([]client.BulkResult, error) {
+			t.Error("expected no update in dry-run mode")
+			return nil, nil
+		},
+	}
+
+	tool := NewBulkUpdateEntitiesTool(mock, config.WritesConfig{}, config.DefaultLimits())
+	result := tool.Callback(map[string]interface{}{
+		"type":   "Bug",
+		"items":  []any{map[string]any{"Id": float64(1), "Name": "A"}},
+		"dryRun": true,
+	})
+
+	var preview dryRunPreview
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !preview.DryRun || !strings.HasSuffix(preview.Request.URL, "/Bugs/bulk") || len(preview.Request.Items) != 1 {
+		t.Errorf("unexpected preview: %+v", preview.Request)
+	}
+}
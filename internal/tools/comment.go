@@ -5,14 +5,15 @@ import (
 	"fmt"
 
 	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
 
 	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
 	"github.com/strowk/foxy-contexts/pkg/mcp"
 )
 
 // NewAddCommentTool creates a tool to add a comment to an entity
-func NewAddCommentTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewAddCommentTool(c client.Client, writes config.WritesConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "add_comment",
 			Description: ptr("Add a comment to a Target Process entity. " +
@@ -28,11 +29,12 @@ func NewAddCommentTool(c client.Client) fxctx.Tool {
 						"type":        "string",
 						"description": "Comment text (supports HTML formatting)",
 					},
+					"dryRun": dryRunSchema(),
 				},
 				Required: []string{"entityId", "description"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse entityId
 			entityID, err := getIntArg(args, "entityId")
 			if err != nil {
@@ -45,8 +47,16 @@ func NewAddCommentTool(c client.Client) fxctx.Tool {
 				return errorResult(fmt.Errorf("description parameter is required"))
 			}
 
+			if isDryRun(args, writes) {
+				req, err := c.PreviewCreateComment(ctx, entityID, description)
+				if err != nil {
+					return errorResult(err)
+				}
+				return dryRunResult(req, nil)
+			}
+
 			// Call client
-			comment, err := c.CreateComment(context.Background(), entityID, description)
+			comment, err := c.CreateComment(ctx, entityID, description)
 			if err != nil {
 				return errorResult(err)
 			}
@@ -57,8 +67,8 @@ func NewAddCommentTool(c client.Client) fxctx.Tool {
 }
 
 // NewListCommentsTool creates a tool to list comments for an entity
-func NewListCommentsTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewListCommentsTool(c client.Client, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "list_comments",
 			Description: ptr("List comments for a Target Process entity. " +
@@ -70,12 +80,7 @@ func NewListCommentsTool(c client.Client) fxctx.Tool {
 						"type":        "integer",
 						"description": "Entity ID to list comments for",
 					},
-					"take": {
-						"type":        "integer",
-						"description": "Number of comments to return (default: 25, min: 1, max: 100)",
-						"minimum":     1,
-						"maximum":     100,
-					},
+					"take": takeSchema("comments", limits.CommentsTake),
 					"include": {
 						"type":        "array",
 						"description": "Fields to include in response (default: [Description,CreateDate,Owner])",
@@ -83,29 +88,19 @@ func NewListCommentsTool(c client.Client) fxctx.Tool {
 							"type": "string",
 						},
 					},
+					"noCache": noCacheSchema(),
 				},
 				Required: []string{"entityId"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse entityId
 			entityID, err := getIntArg(args, "entityId")
 			if err != nil {
 				return errorResult(err)
 			}
 
-			// Parse take with default and clamping
-			take := 25 // default
-			if t, err := getIntArg(args, "take"); err == nil {
-				take = t
-			}
-			// Clamp to [1, 100]
-			if take < 1 {
-				take = 1
-			}
-			if take > 100 {
-				take = 100
-			}
+			take := getTakeArg(args, limits.CommentsTake)
 
 			// Parse include with default
 			include := getStringSliceArg(args, "include")
@@ -114,7 +109,7 @@ func NewListCommentsTool(c client.Client) fxctx.Tool {
 			}
 
 			// Call client
-			comments, err := c.ListComments(context.Background(), entityID, take, include)
+			comments, err := c.ListComments(ctx, entityID, take, include)
 			if err != nil {
 				return errorResult(err)
 			}
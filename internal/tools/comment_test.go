@@ -4,10 +4,12 @@ import (
 	"context"
 	"testing"
 
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/testutil"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
 )
 
 func TestAddComment(t *testing.T) {
@@ -24,7 +26,7 @@ func TestAddComment(t *testing.T) {
 		},
 	}
 
-	tool := NewAddCommentTool(mockClient)
+	tool := NewAddCommentTool(mockClient, config.WritesConfig{})
 	result := tool.Callback(map[string]interface{}{
 		"entityId":    float64(456),
 		"description": "Test comment",
@@ -49,7 +51,7 @@ func TestListComments_Defaults(t *testing.T) {
 		},
 	}
 
-	tool := NewListCommentsTool(mockClient)
+	tool := NewListCommentsTool(mockClient, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"entityId": float64(100),
 	})
@@ -72,7 +74,7 @@ func TestListComments_CustomParams(t *testing.T) {
 		},
 	}
 
-	tool := NewListCommentsTool(mockClient)
+	tool := NewListCommentsTool(mockClient, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"entityId": float64(200),
 		"take":     float64(50),
@@ -82,3 +84,24 @@ func TestListComments_CustomParams(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Nil(t, result.IsError)
 }
+
+func TestAddComment_DryRun(t *testing.T) {
+	mockClient := &testutil.MockClient{
+		CreateCommentFn: func(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
+			t.Fatal("CreateComment must not be called in dry-run mode")
+			return nil, nil
+		},
+	}
+
+	tool := NewAddCommentTool(mockClient, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{
+		"entityId":    float64(100),
+		"description": "Draft",
+		"dryRun":      true,
+	})
+
+	assert.Nil(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"dryRun": true`)
+	assert.Contains(t, text, "https://test.tpondemand.com/api/v1/Comments")
+}
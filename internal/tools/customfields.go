@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/domain/entity"
+)
+
+// validateCreateCustomFields checks the customFields of a create against the
+// definitions of the process of project, a {"Id": ...} reference. When the
+// project is not given or cannot be read, a value passes if any process
+// accepts it, and required fields are not enforced.
+func validateCreateCustomFields(ctx context.Context, c client.Client, entityType entity.Type, project any, values map[string]any) error {
+	defs, ok := customFieldDefinitions(ctx, c, entityType)
+	if !ok {
+		return nil
+	}
+	processID := projectProcess(ctx, c, project)
+	if processID != 0 {
+		defs = entity.CustomFieldsForProcess(defs, processID)
+	}
+	return customFieldError(entity.ValidateCustomFields(defs, values, processID != 0))
+}
+
+// validateUpdateFields checks the custom fields of an update payload against
+// the definitions of the process of the entity's project, or of every
+// process when it cannot be read
+func validateUpdateFields(ctx context.Context, c client.Client, entityType entity.Type, id int, fields map[string]any) error {
+	defs, ok := customFieldDefinitions(ctx, c, entityType)
+	if !ok {
+		return nil
+	}
+	if processID := entityProcess(ctx, c, entityType, id); processID != 0 {
+		defs = entity.CustomFieldsForProcess(defs, processID)
+	}
+	return customFieldError(entity.ValidateFields(defs, fields))
+}
+
+// customFieldDefinitions loads the custom fields of entityType. Without
+// definitions nothing can be checked, so a failure to load them lets the
+// write through for TP to validate.
+func customFieldDefinitions(ctx context.Context, c client.Client, entityType entity.Type) ([]entity.CustomFieldDefinition, bool) {
+	defs, err := c.GetCustomFields(ctx, entityType)
+	if err != nil || len(defs) == 0 {
+		return nil, false
+	}
+	return defs, true
+}
+
+// entityProcess returns the ID of the process of the project of the entity,
+// or 0
+func entityProcess(ctx context.Context, c client.Client, entityType entity.Type, id int) int {
+	e, err := c.GetEntity(ctx, entityType, id, []string{"Project[Process]"})
+	if err != nil {
+		return 0
+	}
+	return processOf(e["Project"])
+}
+
+// projectProcess returns the ID of the process of project, or 0
+func projectProcess(ctx context.Context, c client.Client, project any) int {
+	ref, ok := project.(map[string]any)
+	if !ok {
+		return 0
+	}
+	id, err := getIntArg(ref, "Id")
+	if err != nil {
+		return 0
+	}
+	p, err := c.GetEntity(ctx, entity.TypeProject, id, []string{"Process"})
+	if err != nil {
+		return 0
+	}
+	return processOf(p)
+}
+
+// processOf returns the ID of the Process a project, as returned by TP,
+// references, or 0
+func processOf(project any) int {
+	p, _ := project.(map[string]any)
+	process, _ := p["Process"].(map[string]any)
+	processID, _ := getIntArg(process, "Id")
+	return processID
+}
+
+func customFieldError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("custom field check failed, nothing was sent:\n%w\n\n"+
+		"inspect_object with action list_custom_fields shows the fields, types and options", err)
+}
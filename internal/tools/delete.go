@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// confirmationTTL is how long a delete confirmation token stays valid
+const confirmationTTL = 5 * time.Minute
+
+// deletePreview is the result of delete_entity called without a token
+type deletePreview struct {
+	Request           *client.WriteRequest `json:"request"`
+	Entity            map[string]any       `json:"entity"`
+	ConfirmationToken string               `json:"confirmationToken"`
+	ExpiresIn         string               `json:"expiresIn"`
+}
+
+// deleteResult is the result of a confirmed delete_entity call
+type deleteResult struct {
+	Deleted bool           `json:"deleted"`
+	Entity  map[string]any `json:"entity"`
+}
+
+// confirmations issues and checks the tokens binding a delete to the
+// preview that showed it. The key lives only in memory, so tokens do not
+// survive a restart.
+type confirmations struct {
+	key []byte
+	now func() time.Time
+}
+
+func newConfirmations() *confirmations {
+	key := make([]byte, 32)
+	// Since Go 1.24, rand.Read never returns an error
+	_, _ = rand.Read(key)
+	return &confirmations{key: key, now: time.Now}
+}
+
+// issue returns a token confirming req until confirmationTTL from now.
+// req's URL names the instance, the type and the ID, so a token cannot
+// confirm the deletion of any other entity.
+func (c *confirmations) issue(req *client.WriteRequest) string {
+	expires := strconv.FormatInt(c.now().Add(confirmationTTL).Unix(), 10)
+	return expires + "." + c.sign(req, expires)
+}
+
+// check returns an error unless token was issued for req and has not expired
+func (c *confirmations) check(req *client.WriteRequest, token string) error {
+	expires, sig, ok := strings.Cut(token, ".")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if !ok || err != nil || !hmac.Equal([]byte(sig), []byte(c.sign(req, expires))) {
+		return fmt.Errorf("confirmation token does not match this entity; call delete_entity without a token to preview the deletion and get one")
+	}
+	if !c.now().Before(time.Unix(unix, 0)) {
+		return fmt.Errorf("confirmation token has expired; call delete_entity without a token to preview the deletion again")
+	}
+	return nil
+}
+
+func (c *confirmations) sign(req *client.WriteRequest, expires string) string {
+	mac := hmac.New(sha256.New, c.key)
+	fmt.Fprintf(mac, "%s %s %s", req.Method, req.URL, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewDeleteEntityTool creates a tool to delete an entity. Deleting takes two
+// calls: the first previews the entity and returns a confirmation token, the
+// second passes the token back.
+func NewDeleteEntityTool(c client.Client, writes config.WritesConfig) fxctx.Tool {
+	return newDeleteEntityTool(c, writes, newConfirmations())
+}
+
+func newDeleteEntityTool(c client.Client, writes config.WritesConfig, confirm *confirmations) fxctx.Tool {
+	return newTool(
+		&mcp.Tool{
+			Name: "delete_entity",
+			Description: ptr("Delete a Target Process entity by type and ID. " +
+				"Call it first without confirmationToken to preview the entity and get a token, " +
+				"then again with the token to delete it. " +
+				fmt.Sprintf("Tokens expire after %s. ", confirmationTTL) +
+				"Returns the deleted entity's default fields, custom fields included, to help recreate it; " +
+				"collections such as assigned users, comments and attachments are not part of it."),
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]map[string]interface{}{
+					"type": {
+						"type":        "string",
+						"description": "Entity type (e.g., UserStory, Bug, Task, Feature)",
+						"enum":        entityTypeStrings(),
+					},
+					"id": {
+						"type":        "integer",
+						"description": "Entity ID to delete",
+					},
+					"confirmationToken": {
+						"type":        "string",
+						"description": "Token returned by a preview call for the same entity; omit it to preview",
+					},
+					"dryRun": dryRunSchema(),
+				},
+				Required: []string{"type", "id"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			typeStr := getStringArg(args, "type")
+			if typeStr == "" {
+				return errorResult(fmt.Errorf("type parameter is required"))
+			}
+			entityType, err := entity.ParseType(typeStr)
+			if err != nil {
+				return errorResult(err)
+			}
+			id, err := getIntArg(args, "id")
+			if err != nil {
+				return errorResult(err)
+			}
+
+			req, err := c.PreviewDeleteEntity(ctx, entityType, id)
+			if err != nil {
+				return errorResult(err)
+			}
+			if isDryRun(args, writes) {
+				return dryRunResult(req, nil)
+			}
+
+			token := getStringArg(args, "confirmationToken")
+			if token != "" {
+				if err := confirm.check(req, token); err != nil {
+					return errorResult(err)
+				}
+			}
+
+			snapshot, err := c.GetEntity(client.WithoutCache(ctx), entityType, id, nil)
+			if err != nil {
+				return errorResult(err)
+			}
+			if token == "" {
+				return jsonResult(deletePreview{
+					Request:           req,
+					Entity:            snapshot,
+					ConfirmationToken: confirm.issue(req),
+					ExpiresIn:         confirmationTTL.String(),
+				})
+			}
+
+			if err := c.DeleteEntity(ctx, entityType, id); err != nil {
+				return errorResult(err)
+			}
+			return jsonResult(deleteResult{Deleted: true, Entity: snapshot})
+		},
+	)
+}
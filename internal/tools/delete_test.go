@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/testutil"
+
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// deleteMock serves a snapshot of any entity and counts deletes
+func deleteMock(deleted *[]int) *testutil.MockClient {
+	return &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{"Id": float64(id), "Name": "Obsolete"}, nil
+		},
+		DeleteEntityFn: func(ctx context.Context, entityType entity.Type, id int) error {
+			*deleted = append(*deleted, id)
+			return nil
+		},
+	}
+}
+
+// previewToken calls tool without a token and returns the token it issued
+func previewToken(t *testing.T, tool interface {
+	Callback(map[string]interface{}) *mcp.CallToolResult
+}, id int) string {
+	t.Helper()
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(id)})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected a preview, got error: %v", result.Content)
+	}
+	var preview deletePreview
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview); err != nil {
+		t.Fatalf("failed to unmarshal preview: %v", err)
+	}
+	if preview.Entity["Name"] != "Obsolete" || preview.Request.Method != "DELETE" {
+		t.Errorf("unexpected preview: %+v", preview)
+	}
+	return preview.ConfirmationToken
+}
+
+func TestDeleteEntityTool_PreviewThenConfirm(t *testing.T) {
+	var deleted []int
+	tool := NewDeleteEntityTool(deleteMock(&deleted), config.WritesConfig{})
+
+	token := previewToken(t, tool, 42)
+	if len(deleted) != 0 {
+		t.Fatal("expected the preview not to delete anything")
+	}
+
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "confirmationToken": token})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var res deleteResult
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &res); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !res.Deleted || res.Entity["Name"] != "Obsolete" {
+		t.Errorf("expected the deleted entity's snapshot, got %+v", res)
+	}
+	if len(deleted) != 1 || deleted[0] != 42 {
+		t.Errorf("expected entity 42 to be deleted, got %v", deleted)
+	}
+}
+
+func TestDeleteEntityTool_RejectsBadTokens(t *testing.T) {
+	var deleted []int
+	confirm := newConfirmations()
+	tool := newDeleteEntityTool(deleteMock(&deleted), config.WritesConfig{}, confirm)
+	token := previewToken(t, tool, 42)
+
+	tests := []struct {
+		name  string
+		id    int
+		token string
+		want  string
+	}{
+		{"other entity", 43, token, "does not match"},
+		{"forged", 42, "9999999999.abc", "does not match"},
+		{"malformed", 42, "not-a-token", "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(tt.id), "confirmationToken": tt.token})
+			if result.IsError == nil || !*result.IsError {
+				t.Fatal("expected an error")
+			}
+			if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, text)
+			}
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		confirm.now = func() time.Time { return time.Now().Add(confirmationTTL + time.Second) }
+		t.Cleanup(func() { confirm.now = time.Now })
+		result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "confirmationToken": token})
+		if result.IsError == nil || !*result.IsError {
+			t.Fatal("expected an error")
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "expired") {
+			t.Errorf("expected an expiry error, got %q", text)
+		}
+	})
+
+	if len(deleted) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", deleted)
+	}
+}
+
+func TestDeleteEntityTool_DryRun(t *testing.T) {
+	var deleted []int
+	tool := NewDeleteEntityTool(deleteMock(&deleted), config.WritesConfig{DryRun: true})
+
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "confirmationToken": "anything"})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var preview dryRunPreview
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !preview.DryRun || preview.Request.Method != "DELETE" {
+		t.Errorf("expected a dry-run preview, got %+v", preview)
+	}
+	if len(deleted) != 0 {
+		t.Errorf("expected nothing to be deleted, got %v", deleted)
+	}
+}
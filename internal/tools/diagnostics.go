@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"context"
+
+	"tp-mcp-go/internal/client"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// NewGetDiagnosticsTool creates a tool that reports the health of the connection to each instance
+func NewGetDiagnosticsTool(c client.Client) fxctx.Tool {
+	return newTool(
+		&mcp.Tool{
+			Name: "get_diagnostics",
+			Description: ptr("Report the state of the connection to each Target Process instance, including its circuit breaker. " +
+				"Use this when calls fail fast with a circuit breaker error to see when the next attempt is allowed."),
+			InputSchema: mcp.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]map[string]interface{}{},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			if reporter, ok := c.(client.DiagnosticsReporter); ok {
+				if diagnostics := reporter.Diagnostics(); len(diagnostics) > 0 {
+					return jsonResult(diagnostics)
+				}
+			}
+			return jsonResult([]client.Diagnostics{{Instance: "default"}})
+		},
+	)
+}
@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/testutil"
+
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// diagnosticsClient is a mock client that reports fixed diagnostics
+type diagnosticsClient struct {
+	*testutil.MockClient
+	diagnostics []client.Diagnostics
+}
+
+func (c *diagnosticsClient) Diagnostics() []client.Diagnostics {
+	return c.diagnostics
+}
+
+func TestGetDiagnostics_ReportsBreakerState(t *testing.T) {
+	mock := &diagnosticsClient{
+		MockClient: &testutil.MockClient{},
+		diagnostics: []client.Diagnostics{
+			{Instance: "default", Domain: "a.tpondemand.com", CircuitBreaker: &client.BreakerStatus{State: "open", ConsecutiveFailures: 5, FailureThreshold: 5, RetryIn: "12s"}},
+			{Instance: "staging", Domain: "b.tpondemand.com"},
+		},
+	}
+
+	result := NewGetDiagnosticsTool(mock).Callback(map[string]interface{}{})
+	if result.IsError != nil && *result.IsError {
+		t.Fatal("expected success, got error")
+	}
+
+	var got []client.Diagnostics
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 instances, got %d", len(got))
+	}
+	if got[0].CircuitBreaker == nil || got[0].CircuitBreaker.State != "open" || got[0].CircuitBreaker.RetryIn != "12s" {
+		t.Errorf("unexpected breaker status: %+v", got[0].CircuitBreaker)
+	}
+	if got[1].Instance != "staging" || got[1].CircuitBreaker != nil {
+		t.Errorf("unexpected second instance: %+v", got[1])
+	}
+}
+
+func TestGetDiagnostics_WithoutReporter(t *testing.T) {
+	result := NewGetDiagnosticsTool(&testutil.MockClient{}).Callback(map[string]interface{}{})
+	if result.IsError != nil && *result.IsError {
+		t.Fatal("expected success, got error")
+	}
+
+	var got []client.Diagnostics
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if len(got) != 1 || got[0].Instance != "default" {
+		t.Errorf("unexpected diagnostics: %+v", got)
+	}
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -13,7 +14,7 @@ import (
 // NewGetDocumentationTool creates the get_documentation tool
 // NOTE: NO client dependency — uses embedded docs only
 func NewGetDocumentationTool() fxctx.Tool {
-	return fxctx.NewTool(
+	return newLocalTool(
 		&mcp.Tool{
 			Name:        "get_documentation",
 			Description: ptr("Access embedded documentation for the TP MCP server"),
@@ -29,7 +30,7 @@ func NewGetDocumentationTool() fxctx.Tool {
 				Required: []string{"topic"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			topic := getStringArg(args, "topic")
 
 			// For "overview" or invalid topic, return getting started with topic list
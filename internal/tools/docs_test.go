@@ -158,3 +158,9 @@ func TestGetDocumentationTool(t *testing.T) {
 		}
 	})
 }
+
+func TestGetDocumentationTool_TakesNoInstance(t *testing.T) {
+	if _, ok := NewGetDocumentationTool().GetMcpTool().InputSchema.Properties["instance"]; ok {
+		t.Error("expected get_documentation, which never calls Target Process, to take no instance argument")
+	}
+}
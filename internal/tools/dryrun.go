@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"reflect"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// dryRunSchema describes the dryRun argument shared by the write tools
+func dryRunSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "boolean",
+		"description": "Return the request that would be sent (method, URL, payload and, for updates, a diff against the current entity) without changing anything",
+	}
+}
+
+// isDryRun reports whether a write tool should only preview its request,
+// either because the call asks for it or because the server is configured so
+func isDryRun(args map[string]interface{}, writes config.WritesConfig) bool {
+	return writes.DryRun || getBoolArg(args, "dryRun")
+}
+
+// fieldChange is one entry of an update diff
+type fieldChange struct {
+	Current any `json:"current"`
+	New     any `json:"new"`
+}
+
+// dryRunPreview is the result of a write tool in dry-run mode
+type dryRunPreview struct {
+	DryRun  bool                   `json:"dryRun"`
+	Request *client.WriteRequest   `json:"request"`
+	Diff    map[string]fieldChange `json:"diff,omitempty"`
+}
+
+// dryRunResult reports req, and diff for updates, without sending anything
+func dryRunResult(req *client.WriteRequest, diff map[string]fieldChange) *mcp.CallToolResult {
+	return jsonResult(dryRunPreview{DryRun: true, Request: req, Diff: diff})
+}
+
+// diffFields lists the fields of body whose value differs from current.
+// A reference such as {"Id": 5} only compares the keys it sets, so it matches
+// the expanded {"Id": 5, "Name": "..."} the API returns.
+func diffFields(current, body map[string]any) map[string]fieldChange {
+	diff := make(map[string]fieldChange)
+	for field, next := range body {
+		if !sameValue(current[field], next) {
+			diff[field] = fieldChange{Current: current[field], New: next}
+		}
+	}
+	return diff
+}
+
+// sameValue reports whether setting next would leave current unchanged
+func sameValue(current, next any) bool {
+	cm, cok := current.(map[string]any)
+	nm, nok := next.(map[string]any)
+	if cok && nok {
+		for k, v := range nm {
+			if !sameValue(cm[k], v) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(current, next)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/entity"
 
 	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
@@ -13,7 +14,7 @@ import (
 
 // NewGetEntityTool creates a tool to get a single entity by ID
 func NewGetEntityTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+	return newTool(
 		&mcp.Tool{
 			Name: "get_entity",
 			Description: ptr("Get a single Target Process entity by type and ID. " +
@@ -37,11 +38,12 @@ func NewGetEntityTool(c client.Client) fxctx.Tool {
 							"type": "string",
 						},
 					},
+					"noCache": noCacheSchema(),
 				},
 				Required: []string{"type", "id"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse and validate entity type
 			typeStr := getStringArg(args, "type")
 			if typeStr == "" {
@@ -63,7 +65,7 @@ func NewGetEntityTool(c client.Client) fxctx.Tool {
 			include := getStringSliceArg(args, "include")
 
 			// Call client
-			result, err := c.GetEntity(context.Background(), entityType, id, include)
+			result, err := c.GetEntity(ctx, entityType, id, include)
 			if err != nil {
 				return errorResult(err)
 			}
@@ -74,8 +76,8 @@ func NewGetEntityTool(c client.Client) fxctx.Tool {
 }
 
 // NewCreateEntityTool creates a tool to create a new entity
-func NewCreateEntityTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewCreateEntityTool(c client.Client, writes config.WritesConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "create_entity",
 			Description: ptr("Create a new Target Process entity. " +
@@ -125,13 +127,14 @@ func NewCreateEntityTool(c client.Client) fxctx.Tool {
 					},
 					"customFields": {
 						"type":        "object",
-						"description": "Custom fields as key-value pairs to merge into the entity data",
+						"description": "Custom fields as key-value pairs to merge into the entity data; names, value types and dropdown options are checked against the project's process before sending",
 					},
+					"dryRun": dryRunSchema(),
 				},
 				Required: []string{"type", "name"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse and validate entity type
 			typeStr := getStringArg(args, "type")
 			if typeStr == "" {
@@ -173,17 +176,25 @@ func NewCreateEntityTool(c client.Client) fxctx.Tool {
 				data["AssignedUser"] = assignedUser
 			}
 
-			// Optional: CustomFields (merge into data map)
-			if customFields := getAnyArg(args, "customFields"); customFields != nil {
-				if cfMap, ok := customFields.(map[string]any); ok {
-					for k, v := range cfMap {
-						data[k] = v
-					}
+			// Optional: CustomFields (validate, then merge into data map)
+			cfMap, _ := getAnyArg(args, "customFields").(map[string]any)
+			if err := validateCreateCustomFields(ctx, c, entityType, data["Project"], cfMap); err != nil {
+				return errorResult(err)
+			}
+			for k, v := range cfMap {
+				data[k] = v
+			}
+
+			if isDryRun(args, writes) {
+				req, err := c.PreviewCreateEntity(ctx, entityType, data)
+				if err != nil {
+					return errorResult(err)
 				}
+				return dryRunResult(req, nil)
 			}
 
 			// Call client
-			result, err := c.CreateEntity(context.Background(), entityType, data)
+			result, err := c.CreateEntity(ctx, entityType, data)
 			if err != nil {
 				return errorResult(err)
 			}
@@ -194,8 +205,8 @@ func NewCreateEntityTool(c client.Client) fxctx.Tool {
 }
 
 // NewUpdateEntityTool creates a tool to update an existing entity
-func NewUpdateEntityTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewUpdateEntityTool(c client.Client, writes config.WritesConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "update_entity",
 			Description: ptr("Update an existing Target Process entity by type and ID. " +
@@ -216,11 +227,12 @@ func NewUpdateEntityTool(c client.Client) fxctx.Tool {
 						"type":        "object",
 						"description": "Fields to update as key-value pairs (e.g., {\"Name\": \"New Name\", \"Description\": \"New Description\"})",
 					},
+					"dryRun": dryRunSchema(),
 				},
 				Required: []string{"type", "id", "fields"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse and validate entity type
 			typeStr := getStringArg(args, "type")
 			if typeStr == "" {
@@ -249,8 +261,24 @@ func NewUpdateEntityTool(c client.Client) fxctx.Tool {
 				return errorResult(fmt.Errorf("fields must be an object"))
 			}
 
+			if err := validateUpdateFields(ctx, c, entityType, id, fieldsMap); err != nil {
+				return errorResult(err)
+			}
+
+			if isDryRun(args, writes) {
+				req, err := c.PreviewUpdateEntity(ctx, entityType, id, fieldsMap)
+				if err != nil {
+					return errorResult(err)
+				}
+				current, err := c.GetEntity(client.WithoutCache(ctx), entityType, id, nil)
+				if err != nil {
+					return errorResult(err)
+				}
+				return dryRunResult(req, diffFields(current, fieldsMap))
+			}
+
 			// Call client
-			result, err := c.UpdateEntity(context.Background(), entityType, id, fieldsMap)
+			result, err := c.UpdateEntity(ctx, entityType, id, fieldsMap)
 			if err != nil {
 				return errorResult(err)
 			}
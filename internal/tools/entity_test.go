@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/testutil"
 
@@ -122,7 +124,7 @@ func TestCreateEntityBuildsDataMap(t *testing.T) {
 		},
 	}
 
-	tool := NewCreateEntityTool(mock)
+	tool := NewCreateEntityTool(mock, config.WritesConfig{})
 	result := tool.Callback(map[string]interface{}{
 		"type":        "UserStory",
 		"name":        "New Story",
@@ -162,24 +164,32 @@ func TestCreateEntityWithCustomFields(t *testing.T) {
 	var capturedData map[string]any
 
 	mock := &testutil.MockClient{
+		// Standard fields sent through customFields must pass the check
+		// against the process's definitions
+		GetCustomFieldsFn: func(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+			return []entity.CustomFieldDefinition{
+				{Name: "Risk", Type: "DropDown", Options: []string{"Low", "High"}, Required: true, ProcessID: 3},
+			}, nil
+		},
 		CreateEntityFn: func(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
 			capturedData = data
 			return map[string]any{"Id": 789}, nil
 		},
 	}
 
-	tool := NewCreateEntityTool(mock)
+	tool := NewCreateEntityTool(mock, config.WritesConfig{})
 	result := tool.Callback(map[string]interface{}{
 		"type": "Bug",
 		"name": "Test Bug",
 		"customFields": map[string]interface{}{
 			"Severity": "High",
 			"Priority": "P1",
+			"Risk":     "Low",
 		},
 	})
 
 	if result.IsError != nil && *result.IsError {
-		t.Fatal("expected success, got error")
+		t.Fatalf("expected success, got error: %s", result.Content[0].(mcp.TextContent).Text)
 	}
 
 	// Verify custom fields are merged
@@ -199,7 +209,7 @@ func TestCreateEntityWithCustomFields(t *testing.T) {
 
 func TestCreateEntityMissingName(t *testing.T) {
 	mock := &testutil.MockClient{}
-	tool := NewCreateEntityTool(mock)
+	tool := NewCreateEntityTool(mock, config.WritesConfig{})
 
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
@@ -220,7 +230,7 @@ func TestCreateEntityWithTeamAndAssignedUser(t *testing.T) {
 		},
 	}
 
-	tool := NewCreateEntityTool(mock)
+	tool := NewCreateEntityTool(mock, config.WritesConfig{})
 	result := tool.Callback(map[string]interface{}{
 		"type":         "Task",
 		"name":         "Test Task",
@@ -265,7 +275,7 @@ func TestUpdateEntityPassesFieldsCorrectly(t *testing.T) {
 		},
 	}
 
-	tool := NewUpdateEntityTool(mock)
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
 	result := tool.Callback(map[string]interface{}{
 		"type": "Bug",
 		"id":   float64(999),
@@ -310,7 +320,7 @@ func TestUpdateEntityPassesFieldsCorrectly(t *testing.T) {
 
 func TestUpdateEntityMissingFields(t *testing.T) {
 	mock := &testutil.MockClient{}
-	tool := NewUpdateEntityTool(mock)
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
 
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
@@ -324,7 +334,7 @@ func TestUpdateEntityMissingFields(t *testing.T) {
 
 func TestUpdateEntityInvalidFieldsType(t *testing.T) {
 	mock := &testutil.MockClient{}
-	tool := NewUpdateEntityTool(mock)
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
 
 	result := tool.Callback(map[string]interface{}{
 		"type":   "UserStory",
@@ -344,7 +354,7 @@ func TestUpdateEntityHandlesError(t *testing.T) {
 		},
 	}
 
-	tool := NewUpdateEntityTool(mock)
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
 	result := tool.Callback(map[string]interface{}{
 		"type":   "UserStory",
 		"id":     float64(123),
@@ -355,3 +365,250 @@ func TestUpdateEntityHandlesError(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestCreateEntityDryRunDoesNotCreate(t *testing.T) {
+	mock := &testutil.MockClient{
+		CreateEntityFn: func(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
+			t.Fatal("CreateEntity must not be called in dry-run mode")
+			return nil, nil
+		},
+	}
+
+	tool := NewCreateEntityTool(mock, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{
+		"type":    "Feature",
+		"name":    "Preview me",
+		"project": map[string]interface{}{"Id": float64(7)},
+		"dryRun":  true,
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	var preview struct {
+		DryRun  bool `json:"dryRun"`
+		Request struct {
+			Method string         `json:"method"`
+			URL    string         `json:"url"`
+			Body   map[string]any `json:"body"`
+		} `json:"request"`
+	}
+	textContent := result.Content[0].(mcp.TextContent)
+	if err := json.Unmarshal([]byte(textContent.Text), &preview); err != nil {
+		t.Fatalf("failed to parse preview: %v", err)
+	}
+	if !preview.DryRun {
+		t.Error("expected dryRun true")
+	}
+	if preview.Request.Method != "POST" || preview.Request.URL != "https://test.tpondemand.com/api/v1/Features" {
+		t.Errorf("unexpected request %s %s", preview.Request.Method, preview.Request.URL)
+	}
+	if preview.Request.Body["Name"] != "Preview me" {
+		t.Errorf("expected Name in payload, got %v", preview.Request.Body)
+	}
+}
+
+func TestCreateEntityGlobalDryRun(t *testing.T) {
+	mock := &testutil.MockClient{
+		CreateEntityFn: func(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
+			t.Fatal("CreateEntity must not be called in dry-run mode")
+			return nil, nil
+		},
+	}
+
+	tool := NewCreateEntityTool(mock, config.WritesConfig{DryRun: true})
+	result := tool.Callback(map[string]interface{}{
+		"type": "Bug",
+		"name": "Preview me",
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+}
+
+func TestUpdateEntityDryRunReportsDiff(t *testing.T) {
+	mock := &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{
+				"Id":          float64(42),
+				"Name":        "Old name",
+				"Description": "Unchanged",
+				"EntityState": map[string]any{"Id": float64(1), "Name": "Open"},
+			}, nil
+		},
+		UpdateEntityFn: func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+			t.Fatal("UpdateEntity must not be called in dry-run mode")
+			return nil, nil
+		},
+	}
+
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{
+		"type": "Bug",
+		"id":   float64(42),
+		"fields": map[string]interface{}{
+			"Name":        "New name",
+			"Description": "Unchanged",
+			"EntityState": map[string]interface{}{"Id": float64(1)},
+		},
+		"dryRun": true,
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	var preview struct {
+		Request struct {
+			URL string `json:"url"`
+		} `json:"request"`
+		Diff map[string]struct {
+			Current any `json:"current"`
+			New     any `json:"new"`
+		} `json:"diff"`
+	}
+	textContent := result.Content[0].(mcp.TextContent)
+	if err := json.Unmarshal([]byte(textContent.Text), &preview); err != nil {
+		t.Fatalf("failed to parse preview: %v", err)
+	}
+	if preview.Request.URL != "https://test.tpondemand.com/api/v1/Bugs/42" {
+		t.Errorf("unexpected URL %s", preview.Request.URL)
+	}
+	if len(preview.Diff) != 1 {
+		t.Fatalf("expected only Name in diff, got %v", preview.Diff)
+	}
+	if change := preview.Diff["Name"]; change.Current != "Old name" || change.New != "New name" {
+		t.Errorf("unexpected Name change %+v", change)
+	}
+}
+
+func TestCreateEntityValidatesCustomFields(t *testing.T) {
+	created := false
+	mock := &testutil.MockClient{
+		GetCustomFieldsFn: func(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+			return []entity.CustomFieldDefinition{
+				{Name: "Risk", Type: "DropDown", Options: []string{"Low", "High"}, Required: true, ProcessID: 3},
+				{Name: "Ticket", Type: "Text", Required: true, ProcessID: 4},
+			}, nil
+		},
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			if entityType != entity.TypeProject || id != 12 {
+				t.Errorf("unexpected lookup of %s %d", entityType, id)
+			}
+			return map[string]any{"Id": float64(12), "Process": map[string]any{"Id": float64(3)}}, nil
+		},
+		CreateEntityFn: func(ctx context.Context, entityType entity.Type, data map[string]any) (map[string]any, error) {
+			created = true
+			return map[string]any{"Id": 1}, nil
+		},
+	}
+	tool := NewCreateEntityTool(mock, config.WritesConfig{})
+
+	result := tool.Callback(map[string]interface{}{
+		"type":         "Bug",
+		"name":         "Test Bug",
+		"project":      map[string]interface{}{"Id": float64(12)},
+		"customFields": map[string]interface{}{"Rsk": "High"},
+	})
+	if result.IsError == nil || !*result.IsError {
+		t.Fatal("expected a validation error")
+	}
+	text := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, `did you mean "Risk"?`) || !strings.Contains(text, "Risk: required custom field is missing") {
+		t.Errorf("unexpected error: %s", text)
+	}
+	// Ticket is required by another process only
+	if strings.Contains(text, "Ticket") {
+		t.Errorf("expected only the project's process to be checked: %s", text)
+	}
+	if created {
+		t.Error("expected nothing to be created")
+	}
+
+	result = tool.Callback(map[string]interface{}{
+		"type":         "Bug",
+		"name":         "Test Bug",
+		"project":      map[string]interface{}{"Id": float64(12)},
+		"customFields": map[string]interface{}{"Risk": "High"},
+	})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if !created {
+		t.Error("expected the entity to be created")
+	}
+}
+
+func TestUpdateEntityValidatesCustomFields(t *testing.T) {
+	mock := &testutil.MockClient{
+		GetCustomFieldsFn: func(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+			return []entity.CustomFieldDefinition{{Name: "Story Points", Type: "Number"}}, nil
+		},
+		UpdateEntityFn: func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+			t.Error("expected nothing to be sent")
+			return nil, nil
+		},
+	}
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
+
+	result := tool.Callback(map[string]interface{}{
+		"type":   "UserStory",
+		"id":     float64(5),
+		"fields": map[string]interface{}{"Name": "Renamed", "Story Points": "lots"},
+	})
+	if result.IsError == nil || !*result.IsError {
+		t.Fatal("expected a validation error")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Story Points: Number field expects a number") {
+		t.Errorf("unexpected error: %s", text)
+	}
+}
+
+func TestUpdateEntityValidatesAgainstTheEntitysProcess(t *testing.T) {
+	updated := false
+	mock := &testutil.MockClient{
+		GetCustomFieldsFn: func(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+			return []entity.CustomFieldDefinition{
+				{Name: "Risk", Type: "DropDown", Options: []string{"Low", "High"}, ProcessID: 3},
+				{Name: "Risk", Type: "DropDown", Options: []string{"Minor", "Major"}, ProcessID: 4},
+			}, nil
+		},
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{"Id": float64(id), "Project": map[string]any{"Process": map[string]any{"Id": float64(4)}}}, nil
+		},
+		UpdateEntityFn: func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+			updated = true
+			return map[string]any{"Id": id}, nil
+		},
+	}
+	tool := NewUpdateEntityTool(mock, config.WritesConfig{})
+
+	result := tool.Callback(map[string]interface{}{
+		"type":   "Bug",
+		"id":     float64(5),
+		"fields": map[string]interface{}{"Risk": "High"},
+	})
+	if result.IsError == nil || !*result.IsError {
+		t.Fatal("expected an option of another process to be rejected")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "options: Minor, Major") {
+		t.Errorf("expected the entity's process options, got %s", text)
+	}
+	if updated {
+		t.Fatal("expected nothing to be sent")
+	}
+
+	result = tool.Callback(map[string]interface{}{
+		"type":   "Bug",
+		"id":     float64(5),
+		"fields": map[string]interface{}{"Risk": "Major"},
+	})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if !updated {
+		t.Error("expected the entity to be updated")
+	}
+}
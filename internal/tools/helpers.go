@@ -5,7 +5,9 @@ import (
 	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/errors"
 
 	"github.com/strowk/foxy-contexts/pkg/mcp"
@@ -22,6 +24,11 @@ func errorResult(err error) *mcp.CallToolResult {
 	if stderrors.As(err, &apiErr) {
 		return apiErrorResult(apiErr)
 	}
+	var openErr *errors.CircuitOpenError
+	if stderrors.As(err, &openErr) {
+		err = fmt.Errorf("%w\n\nTarget Process looks unavailable, so requests are failing fast instead of retrying. "+
+			"Wait before trying again; get_diagnostics shows the breaker state.", err)
+	}
 
 	isErr := true
 	return &mcp.CallToolResult{
@@ -35,28 +42,51 @@ func errorResult(err error) *mcp.CallToolResult {
 	}
 }
 
-// apiErrorResult formats an APIError with actionable hints
+// apiErrorResult formats an APIError with hints tailored to its kind
 func apiErrorResult(apiErr *errors.APIError) *mcp.CallToolResult {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("TP API Error (HTTP %d): %s", apiErr.StatusCode, apiErr.Message))
 
-	switch {
-	case apiErr.StatusCode == 400:
-		sb.WriteString("\n\nThis usually means the query syntax is invalid. Common issues:")
-		sb.WriteString("\n- Boolean values must be single-quoted strings: EntityState.IsFinal eq 'true', EntityState.IsFinal eq 'false'")
-		sb.WriteString("\n- Use 'in' for multiple values: EntityState.Name in ('Open','In Progress') or Id in (1,2,3)")
-		sb.WriteString("\n- String values must be single-quoted: EntityState.Name eq 'Open'")
-		sb.WriteString("\n- Verify field names are valid for this entity type (use inspect_object tool)")
-		sb.WriteString("\n- Collection queries use .Any() syntax: Assignments.Any(GeneralUser.Id eq 123)")
-		sb.WriteString("\n- Sorting: orderBy only accepts a field name (e.g., 'CreateDate'), not 'CreateDate desc' — use orderByField and orderByDirection parameters")
-	case apiErr.StatusCode == 401:
-		sb.WriteString("\n\nAuthentication failed. The access token may be invalid or expired.")
-	case apiErr.StatusCode == 403:
-		sb.WriteString("\n\nPermission denied. The current user may not have access to this resource.")
-	case apiErr.StatusCode == 404:
+	switch typed := errors.Classify(apiErr).(type) {
+	case *errors.ValidationFailedError:
+		switch {
+		case len(typed.Fields) > 0:
+			sb.WriteString(fmt.Sprintf("\n\nTarget Process rejected these fields: %s.", strings.Join(typed.Fields, ", ")))
+			sb.WriteString("\n- Check the field names and value types for this entity type (use inspect_object tool)")
+			sb.WriteString("\n- Reference related entities by Id only, e.g. {\"Project\": {\"Id\": 123}}")
+		case strings.HasPrefix(apiErr.Context, "GET "):
+			sb.WriteString("\n\nThis usually means the query syntax is invalid. Common issues:")
+			sb.WriteString("\n- Boolean values must be single-quoted strings: EntityState.IsFinal eq 'true', EntityState.IsFinal eq 'false'")
+			sb.WriteString("\n- Use 'in' for multiple values: EntityState.Name in ('Open','In Progress') or Id in (1,2,3)")
+			sb.WriteString("\n- String values must be single-quoted: EntityState.Name eq 'Open'")
+			sb.WriteString("\n- Verify field names are valid for this entity type (use inspect_object tool)")
+			sb.WriteString("\n- Collection queries use .Any() syntax: Assignments.Any(GeneralUser.Id eq 123)")
+			sb.WriteString("\n- Sorting: orderBy only accepts a field name (e.g., 'CreateDate'), not 'CreateDate desc' — use orderByField and orderByDirection parameters")
+		default:
+			sb.WriteString("\n\nTarget Process rejected the request. Check that required fields are set and field names are valid for this entity type (use inspect_object tool).")
+		}
+	case *errors.PermissionError:
+		if typed.Unauthenticated() {
+			sb.WriteString("\n\nAuthentication failed. The access token may be invalid or expired.")
+		} else {
+			sb.WriteString("\n\nPermission denied. The current user may not have access to this resource.")
+		}
+	case *errors.NotFoundError:
 		sb.WriteString("\n\nEntity not found. Verify the entity type and ID are correct.")
-	case apiErr.StatusCode >= 500:
-		sb.WriteString("\n\nServer error on the TP side. This is usually temporary — try the request again.")
+	case *errors.ConflictError:
+		sb.WriteString("\n\nThe change conflicts with the entity's current state, which may have been changed by someone else. Fetch the entity again with noCache and retry the change against its current values.")
+	case *errors.RateLimitError:
+		sb.WriteString("\n\nTarget Process is rate limiting requests.")
+		if typed.RetryAfter > 0 {
+			sb.WriteString(fmt.Sprintf(" Wait %s before trying again.", typed.RetryAfter.Round(time.Second)))
+		} else {
+			sb.WriteString(" Wait a moment before trying again.")
+		}
+		sb.WriteString(" Fewer, larger requests (e.g., a higher take or the bulk tools) help stay under the limit.")
+	default:
+		if apiErr.StatusCode >= 500 {
+			sb.WriteString("\n\nServer error on the TP side. This is usually temporary — try the request again.")
+		}
 	}
 
 	if apiErr.Context != "" {
@@ -125,6 +155,12 @@ func getIntArg(args map[string]any, key string) (int, error) {
 	}
 }
 
+// getBoolArg extracts a boolean argument, returning false when it is absent or not a boolean
+func getBoolArg(args map[string]any, key string) bool {
+	b, _ := args[key].(bool)
+	return b
+}
+
 // getStringSliceArg extracts a string slice argument
 func getStringSliceArg(args map[string]any, key string) []string {
 	v, ok := args[key]
@@ -147,3 +183,36 @@ func getStringSliceArg(args map[string]any, key string) []string {
 func getAnyArg(args map[string]any, key string) any {
 	return args[key]
 }
+
+// getTakeArg extracts the optional take argument, applying limit's default and bounds
+func getTakeArg(args map[string]any, limit config.TakeLimit) int {
+	take := limit.Default
+	if t, err := getIntArg(args, "take"); err == nil {
+		take = t
+	}
+	return limit.Clamp(take)
+}
+
+// takeSchema describes the take argument of a listing tool under limit
+func takeSchema(items string, limit config.TakeLimit) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":    "integer",
+		"minimum": 1,
+	}
+	if limit.Max == 0 {
+		schema["description"] = fmt.Sprintf("Number of %s to return (default: %d)", items, limit.Default)
+		return schema
+	}
+	schema["description"] = fmt.Sprintf("Number of %s to return (default: %d, min: 1, max: %d)", items, limit.Default, limit.Max)
+	schema["maximum"] = limit.Max
+	return schema
+}
+
+// noCacheSchema describes the noCache argument of the tools that read
+// responses the client may cache
+func noCacheSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "boolean",
+		"description": "Fetch from Target Process even if a cached response exists, for data that may have changed outside this server",
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	tperrors "tp-mcp-go/internal/domain/errors"
 
@@ -227,6 +228,55 @@ func TestErrorResultWithAPIError(t *testing.T) {
 				"Entity not found",
 			},
 		},
+		{
+			name: "400 error on a write names the rejected fields",
+			apiErr: &tperrors.APIError{
+				StatusCode: 400,
+				Message:    "Validation failed",
+				RawBody:    `{"Message":"Validation failed","Details":{"Items":[{"PropertyName":"Effort","Message":"must be positive"}]}}`,
+				Context:    "POST https://example.com/api/v1/UserStories/1",
+			},
+			expectContains: []string{
+				"TP API Error (HTTP 400)",
+				"rejected these fields: Effort",
+				"inspect_object",
+			},
+		},
+		{
+			name: "403 error includes permission hint",
+			apiErr: &tperrors.APIError{
+				StatusCode: 403,
+				Message:    "Forbidden",
+			},
+			expectContains: []string{
+				"TP API Error (HTTP 403)",
+				"Permission denied",
+			},
+		},
+		{
+			name: "409 error includes conflict hint",
+			apiErr: &tperrors.APIError{
+				StatusCode: 409,
+				Message:    "Conflict",
+			},
+			expectContains: []string{
+				"TP API Error (HTTP 409)",
+				"conflicts with the entity's current state",
+			},
+		},
+		{
+			name: "429 error includes the requested wait",
+			apiErr: &tperrors.APIError{
+				StatusCode: 429,
+				Message:    "Too many requests",
+				RetryAfter: 20 * time.Second,
+			},
+			expectContains: []string{
+				"TP API Error (HTTP 429)",
+				"rate limiting",
+				"Wait 20s",
+			},
+		},
 		{
 			name: "500 error includes server error hint",
 			apiErr: &tperrors.APIError{
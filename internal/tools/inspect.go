@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
 
 	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
 	"github.com/strowk/foxy-contexts/pkg/mcp"
@@ -41,41 +43,42 @@ func extractPropertyDetails(metadata any, entityType, property string) (any, err
 }
 
 // NewInspectObjectTool creates an inspect_object tool using Foxy Contexts DI
-func NewInspectObjectTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewInspectObjectTool(c client.Client, cfg *config.Config) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "inspect_object",
 			Description: ptr("Inspect Target Process metadata, entity types, and properties. " +
 				"Use this tool to discover available entity types, explore properties for a specific type, " +
-				"get detailed information about a specific property, or examine the full API structure."),
+				"get detailed information about a specific property, examine the full API structure, " +
+				"list the custom fields of an entity type with their types and options, " +
+				"or list the Target Process instances this server can reach."),
 			InputSchema: mcp.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]map[string]interface{}{
 					"action": {
 						"type":        "string",
 						"description": "Action to perform",
-						"enum":        []interface{}{"list_types", "get_properties", "get_property_details", "discover_api_structure"},
+						"enum":        []interface{}{"list_types", "get_properties", "get_property_details", "discover_api_structure", "list_custom_fields", "list_instances"},
 					},
 					"entityType": {
 						"type":        "string",
-						"description": "Entity type name (required for get_properties and get_property_details)",
+						"description": "Entity type name (required for get_properties, get_property_details and list_custom_fields)",
 					},
 					"property": {
 						"type":        "string",
 						"description": "Property name (required for get_property_details)",
 					},
+					"noCache": noCacheSchema(),
 				},
 				Required: []string{"action"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			action := getStringArg(args, "action")
 			if action == "" {
 				return errorResult(fmt.Errorf("action parameter is required"))
 			}
 
-			ctx := context.Background()
-
 			switch action {
 			case "list_types":
 				types, err := c.GetValidEntityTypes(ctx)
@@ -132,6 +135,34 @@ func NewInspectObjectTool(c client.Client) fxctx.Tool {
 				}
 				return jsonResult(metadata)
 
+			case "list_custom_fields":
+				typeStr := getStringArg(args, "entityType")
+				if typeStr == "" {
+					return errorResult(fmt.Errorf("entityType parameter is required for list_custom_fields action"))
+				}
+				entityType, err := entity.ParseType(typeStr)
+				if err != nil {
+					return errorResult(err)
+				}
+
+				defs, err := c.GetCustomFields(ctx, entityType)
+				if err != nil {
+					return errorResult(err)
+				}
+				if defs == nil {
+					defs = []entity.CustomFieldDefinition{}
+				}
+				return jsonResult(defs)
+
+			case "list_instances":
+				if lister, ok := c.(client.InstanceLister); ok {
+					if instances := lister.Instances(); len(instances) > 0 {
+						return jsonResult(instances)
+					}
+				}
+				// A plain client only knows its own, default instance
+				return jsonResult([]client.InstanceInfo{{Name: cfg.InstanceName, Domain: cfg.Domain, Default: true}})
+
 			default:
 				return errorResult(fmt.Errorf("unknown action: %s", action))
 			}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"testing"
 
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/testutil"
 
 	"github.com/strowk/foxy-contexts/pkg/mcp"
@@ -48,7 +51,7 @@ func TestInspectObjectListTypes(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action": "list_types",
 	})
@@ -83,7 +86,7 @@ func TestInspectObjectGetProperties(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action":     "get_properties",
 		"entityType": "UserStory",
@@ -118,7 +121,7 @@ func TestInspectObjectGetProperties(t *testing.T) {
 
 func TestInspectObjectGetPropertiesMissingEntityType(t *testing.T) {
 	mock := &testutil.MockClient{}
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action": "get_properties",
 	})
@@ -144,7 +147,7 @@ func TestInspectObjectGetPropertiesInvalidEntityType(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action":     "get_properties",
 		"entityType": "InvalidType",
@@ -171,7 +174,7 @@ func TestInspectObjectGetPropertyDetails(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action":     "get_property_details",
 		"entityType": "UserStory",
@@ -208,7 +211,7 @@ func TestInspectObjectGetPropertyDetailsMissingProperty(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action":     "get_property_details",
 		"entityType": "UserStory",
@@ -235,7 +238,7 @@ func TestInspectObjectGetPropertyDetailsInvalidProperty(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action":     "get_property_details",
 		"entityType": "UserStory",
@@ -263,7 +266,7 @@ func TestInspectObjectDiscoverAPIStructure(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action": "discover_api_structure",
 	})
@@ -298,7 +301,7 @@ func TestInspectObjectListTypesError(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action": "list_types",
 	})
@@ -315,7 +318,7 @@ func TestInspectObjectGetPropertiesError(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action":     "get_properties",
 		"entityType": "UserStory",
@@ -328,7 +331,7 @@ func TestInspectObjectGetPropertiesError(t *testing.T) {
 
 func TestInspectObjectInvalidAction(t *testing.T) {
 	mock := &testutil.MockClient{}
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action": "invalid_action",
 	})
@@ -337,3 +340,72 @@ func TestInspectObjectInvalidAction(t *testing.T) {
 		t.Fatal("expected error for invalid action")
 	}
 }
+
+func TestInspectObjectListInstances(t *testing.T) {
+	c := client.NewContextRouter(
+		client.Instance{Name: "prod", Domain: "prod.tpondemand.com", Client: &testutil.MockClient{}},
+		client.Instance{Name: "sandbox", Domain: "sandbox.tpondemand.com", Client: &testutil.MockClient{}},
+	)
+
+	tool := NewInspectObjectTool(c, &config.Config{})
+	result := tool.Callback(map[string]interface{}{
+		"action": "list_instances",
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatal("expected success, got error")
+	}
+
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatal("expected TextContent")
+	}
+
+	var instances []client.InstanceInfo
+	if err := json.Unmarshal([]byte(textContent.Text), &instances); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if len(instances) != 2 || instances[0].Name != "prod" || !instances[0].Default || instances[1].Name != "sandbox" {
+		t.Errorf("unexpected instances: %+v", instances)
+	}
+}
+
+func TestInspectObjectListInstances_PlainClient(t *testing.T) {
+	tool := NewInspectObjectTool(&testutil.MockClient{}, &config.Config{InstanceName: "prod", Domain: "prod.tpondemand.com"})
+	result := tool.Callback(map[string]interface{}{"action": "list_instances"})
+
+	var instances []client.InstanceInfo
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &instances); err != nil {
+		t.Fatalf("failed to parse result: %v", err)
+	}
+	if len(instances) != 1 || instances[0].Name != "prod" || instances[0].Domain != "prod.tpondemand.com" || !instances[0].Default {
+		t.Errorf("expected the configured default instance, got %+v", instances)
+	}
+}
+
+func TestInspectObjectListCustomFields(t *testing.T) {
+	mock := &testutil.MockClient{
+		GetCustomFieldsFn: func(ctx context.Context, entityType entity.Type) ([]entity.CustomFieldDefinition, error) {
+			if entityType != entity.TypeUserStory {
+				t.Errorf("expected UserStory, got %s", entityType)
+			}
+			return []entity.CustomFieldDefinition{{Name: "Risk", Type: "DropDown", Options: []string{"Low", "High"}}}, nil
+		},
+	}
+	tool := NewInspectObjectTool(mock, &config.Config{})
+	result := tool.Callback(map[string]interface{}{
+		"action":     "list_custom_fields",
+		"entityType": "userstory",
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var defs []entity.CustomFieldDefinition
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &defs); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(defs) != 1 || defs[0].Name != "Risk" || len(defs[0].Options) != 2 {
+		t.Errorf("unexpected definitions: %+v", defs)
+	}
+}
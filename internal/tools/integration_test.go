@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/docs"
 	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/domain/query"
@@ -25,16 +26,22 @@ func TestAllToolsRegistered(t *testing.T) {
 		name string
 		tool fxctx.Tool
 	}{
-		{"search", NewSearchTool(mock)},
+		{"search", NewSearchTool(mock, config.DefaultLimits())},
+		{"query_v2", NewQueryV2Tool(mock, config.DefaultLimits())},
 		{"get_entity", NewGetEntityTool(mock)},
-		{"create_entity", NewCreateEntityTool(mock)},
-		{"update_entity", NewUpdateEntityTool(mock)},
-		{"add_comment", NewAddCommentTool(mock)},
-		{"list_comments", NewListCommentsTool(mock)},
-		{"list_attachments", NewListAttachmentsTool(mock)},
-		{"download_attachment", NewDownloadAttachmentTool(mock)},
-		{"inspect_object", NewInspectObjectTool(mock)},
+		{"create_entity", NewCreateEntityTool(mock, config.WritesConfig{})},
+		{"update_entity", NewUpdateEntityTool(mock, config.WritesConfig{})},
+		{"change_state", NewChangeStateTool(mock, config.WritesConfig{})},
+		{"bulk_create_entities", NewBulkCreateEntitiesTool(mock, config.WritesConfig{}, config.DefaultLimits())},
+		{"bulk_update_entities", NewBulkUpdateEntitiesTool(mock, config.WritesConfig{}, config.DefaultLimits())},
+		{"delete_entity", NewDeleteEntityTool(mock, config.WritesConfig{})},
+		{"add_comment", NewAddCommentTool(mock, config.WritesConfig{})},
+		{"list_comments", NewListCommentsTool(mock, config.DefaultLimits())},
+		{"list_attachments", NewListAttachmentsTool(mock, config.DefaultLimits())},
+		{"download_attachment", NewDownloadAttachmentTool(mock, config.DefaultLimits())},
+		{"inspect_object", NewInspectObjectTool(mock, &config.Config{})},
 		{"get_documentation", NewGetDocumentationTool()},
+		{"get_diagnostics", NewGetDiagnosticsTool(mock)},
 	}
 
 	for _, tt := range tools {
@@ -53,7 +60,7 @@ func TestSearchToolIntegration(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
 	})
@@ -196,7 +203,7 @@ func TestInspectListTypes(t *testing.T) {
 		},
 	}
 
-	tool := NewInspectObjectTool(mock)
+	tool := NewInspectObjectTool(mock, &config.Config{})
 	result := tool.Callback(map[string]interface{}{
 		"action": "list_types",
 	})
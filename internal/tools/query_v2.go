@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// NewQueryV2Tool creates a tool that queries the Target Process v2 API
+func NewQueryV2Tool(c client.Client, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
+		&mcp.Tool{
+			Name: "query_v2",
+			Description: ptr("Query Target Process entities through the v2 API, which returns compact, shaped results. " +
+				"Use 'select' to pick fields, rename them, follow references and aggregate collections in one call, e.g. " +
+				"{id,name,state:entityState.name,openTasks:tasks.where(entityState.isFinal==false).count}. " +
+				"v2 syntax differs from search: field names are camelCase and 'where' uses ==, !=, <, >, and, or. " +
+				"Returns paginated results with cursor."),
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]map[string]interface{}{
+					"type": {
+						"type":        "string",
+						"description": "Entity type to query (e.g., UserStory, Bug, Task, Feature)",
+						"enum":        entityTypeStrings(),
+					},
+					"select": {
+						"type":        "string",
+						"description": "v2 projection, e.g. {id,name,project:project.name,effort,tasksCount:tasks.count}. Defaults to the API's standard fields.",
+					},
+					"where": {
+						"type":        "string",
+						"description": "v2 filter expression, e.g. entityState.isFinal==false and project.id==123",
+					},
+					"orderBy": {
+						"type":        "string",
+						"description": "v2 sort expression, e.g. 'createDate desc' or 'priority.importance'",
+					},
+					"take": takeSchema("items", limits.SearchTake),
+					"skip": {
+						"type":        "integer",
+						"description": "Number of items to skip before the first one returned",
+						"minimum":     0,
+					},
+					"cursor": {
+						"type":        "string",
+						"description": "Pagination cursor from previous response. When provided, all other query params are ignored.",
+					},
+				},
+				Required: []string{"type"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			typeStr := getStringArg(args, "type")
+			if typeStr == "" {
+				return errorResult(fmt.Errorf("type parameter is required"))
+			}
+			entityType, err := entity.ParseType(typeStr)
+			if err != nil {
+				return errorResult(err)
+			}
+
+			req := query.V2Request{
+				EntityType: entityType,
+				Cursor:     getStringArg(args, "cursor"),
+			}
+			if req.Cursor == "" {
+				req.Select = getStringArg(args, "select")
+				req.Where = getStringArg(args, "where")
+				req.OrderBy = getStringArg(args, "orderBy")
+				req.Take = getTakeArg(args, limits.SearchTake)
+				if skip, err := getIntArg(args, "skip"); err == nil {
+					if skip < 0 {
+						return errorResult(fmt.Errorf("skip must not be negative, got %d", skip))
+					}
+					req.Skip = skip
+				}
+			}
+
+			resp, err := c.QueryV2(ctx, req)
+			if err != nil {
+				return errorResult(err)
+			}
+			return jsonResult(resp)
+		},
+	)
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/domain/query"
+	"tp-mcp-go/internal/testutil"
+
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+func TestQueryV2ToolPassesQuery(t *testing.T) {
+	var captured query.V2Request
+	mock := &testutil.MockClient{
+		QueryV2Fn: func(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error) {
+			captured = req
+			return &query.PaginatedResponse{
+				Items:      []map[string]any{{"id": float64(1), "name": "Story", "openTasks": float64(2)}},
+				Pagination: query.PaginationMeta{Returned: 1},
+			}, nil
+		},
+	}
+
+	tool := NewQueryV2Tool(mock, config.DefaultLimits())
+	result := tool.Callback(map[string]interface{}{
+		"type":    "UserStory",
+		"select":  "{id,name,openTasks:tasks.where(entityState.isFinal==false).count}",
+		"where":   "project.id==10",
+		"orderBy": "createDate desc",
+		"take":    float64(5),
+		"skip":    float64(10),
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	want := query.V2Request{
+		EntityType: entity.TypeUserStory,
+		Select:     "{id,name,openTasks:tasks.where(entityState.isFinal==false).count}",
+		Where:      "project.id==10",
+		OrderBy:    "createDate desc",
+		Take:       5,
+		Skip:       10,
+	}
+	if captured != want {
+		t.Errorf("request = %+v, want %+v", captured, want)
+	}
+
+	var response query.PaginatedResponse
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(response.Items) != 1 || response.Items[0]["openTasks"] != float64(2) {
+		t.Errorf("unexpected items: %v", response.Items)
+	}
+}
+
+func TestQueryV2ToolCursorIgnoresOtherParams(t *testing.T) {
+	var captured query.V2Request
+	mock := &testutil.MockClient{
+		QueryV2Fn: func(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error) {
+			captured = req
+			return &query.PaginatedResponse{}, nil
+		},
+	}
+
+	tool := NewQueryV2Tool(mock, config.DefaultLimits())
+	tool.Callback(map[string]interface{}{
+		"type":   "Bug",
+		"select": "{id}",
+		"cursor": "https://test.tpondemand.com/api/v2/Bug?skip=100",
+	})
+
+	if captured.Cursor == "" || captured.Select != "" || captured.Take != 0 {
+		t.Errorf("expected only the cursor to be passed, got %+v", captured)
+	}
+}
+
+func TestQueryV2ToolErrors(t *testing.T) {
+	mock := &testutil.MockClient{
+		QueryV2Fn: func(ctx context.Context, req query.V2Request) (*query.PaginatedResponse, error) {
+			return nil, errors.New("bad select")
+		},
+	}
+	tool := NewQueryV2Tool(mock, config.DefaultLimits())
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"missing type", map[string]interface{}{}},
+		{"invalid type", map[string]interface{}{"type": "Nope"}},
+		{"negative skip", map[string]interface{}{"type": "Bug", "skip": float64(-1)}},
+		{"client error", map[string]interface{}{"type": "Bug"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tool.Callback(tt.args)
+			if result.IsError == nil || !*result.IsError {
+				t.Error("expected an error result")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+
+	"tp-mcp-go/internal/config"
+)
+
+// Registration describes a tool the server can register
+type Registration struct {
+	Name string
+	// ReadOnly is true for tools that never modify Target Process data
+	ReadOnly bool
+	// Constructor builds the tool; it is passed to foxy-contexts' WithTool,
+	// which injects its parameters
+	Constructor interface{}
+}
+
+// Registrations lists every tool in registration order
+func Registrations() []Registration {
+	return []Registration{
+		{Name: "search", ReadOnly: true, Constructor: NewSearchTool},
+		{Name: "query_v2", ReadOnly: true, Constructor: NewQueryV2Tool},
+		{Name: "get_entity", ReadOnly: true, Constructor: NewGetEntityTool},
+		{Name: "create_entity", Constructor: NewCreateEntityTool},
+		{Name: "update_entity", Constructor: NewUpdateEntityTool},
+		{Name: "change_state", Constructor: NewChangeStateTool},
+		{Name: "bulk_create_entities", Constructor: NewBulkCreateEntitiesTool},
+		{Name: "bulk_update_entities", Constructor: NewBulkUpdateEntitiesTool},
+		{Name: "delete_entity", Constructor: NewDeleteEntityTool},
+		{Name: "add_comment", Constructor: NewAddCommentTool},
+		{Name: "list_comments", ReadOnly: true, Constructor: NewListCommentsTool},
+		{Name: "list_attachments", ReadOnly: true, Constructor: NewListAttachmentsTool},
+		{Name: "download_attachment", ReadOnly: true, Constructor: NewDownloadAttachmentTool},
+		{Name: "inspect_object", ReadOnly: true, Constructor: NewInspectObjectTool},
+		{Name: "get_documentation", ReadOnly: true, Constructor: NewGetDocumentationTool},
+		{Name: "get_diagnostics", ReadOnly: true, Constructor: NewGetDiagnosticsTool},
+	}
+}
+
+// Enabled returns the registrations that policy lets the server register.
+// Naming an unknown tool, or allowing a tool that read-only mode excludes, is
+// an error so that a typo cannot silently widen or narrow the tool set.
+func Enabled(policy config.ToolsConfig) ([]Registration, error) {
+	all := Registrations()
+	byName := make(map[string]Registration, len(all))
+	for _, r := range all {
+		byName[r.Name] = r
+	}
+
+	for _, name := range slices.Concat(policy.Allow, policy.Deny) {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("unknown tool %q in tool policy", name)
+		}
+	}
+	if policy.ReadOnly {
+		for _, name := range policy.Allow {
+			if !byName[name].ReadOnly {
+				return nil, fmt.Errorf("tool %q is allowed but read-only mode disables it", name)
+			}
+		}
+	}
+
+	var enabled []Registration
+	for _, r := range all {
+		switch {
+		case policy.ReadOnly && !r.ReadOnly:
+		case len(policy.Allow) > 0 && !slices.Contains(policy.Allow, r.Name):
+		case slices.Contains(policy.Deny, r.Name):
+		default:
+			enabled = append(enabled, r)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("tool policy disables every tool")
+	}
+	return enabled, nil
+}
@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/testutil"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+)
+
+func enabledNames(t *testing.T, policy config.ToolsConfig) []string {
+	t.Helper()
+	enabled, err := Enabled(policy)
+	if err != nil {
+		t.Fatalf("Enabled() unexpected error: %v", err)
+	}
+	names := make([]string, len(enabled))
+	for i, r := range enabled {
+		names[i] = r.Name
+	}
+	return names
+}
+
+func TestEnabled_DefaultRegistersEverything(t *testing.T) {
+	if got := enabledNames(t, config.ToolsConfig{}); len(got) != len(Registrations()) {
+		t.Errorf("expected all %d tools, got %v", len(Registrations()), got)
+	}
+}
+
+func TestEnabled_ReadOnly(t *testing.T) {
+	got := enabledNames(t, config.ToolsConfig{ReadOnly: true})
+	want := []string{"search", "query_v2", "get_entity", "list_comments", "list_attachments", "download_attachment", "inspect_object", "get_documentation", "get_diagnostics"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("tool %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEnabled_AllowAndDeny(t *testing.T) {
+	got := enabledNames(t, config.ToolsConfig{
+		Allow: []string{"search", "get_entity", "add_comment"},
+		Deny:  []string{"add_comment"},
+	})
+	if len(got) != 2 || got[0] != "search" || got[1] != "get_entity" {
+		t.Errorf("expected [search get_entity], got %v", got)
+	}
+}
+
+func TestEnabled_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy config.ToolsConfig
+	}{
+		{"unknown allowed tool", config.ToolsConfig{Allow: []string{"serach"}}},
+		{"unknown denied tool", config.ToolsConfig{Deny: []string{"drop_database"}}},
+		{"write tool allowed in read-only mode", config.ToolsConfig{ReadOnly: true, Allow: []string{"update_entity"}}},
+		{"nothing left", config.ToolsConfig{Allow: []string{"search"}, Deny: []string{"search"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Enabled(tt.policy); err == nil {
+				t.Fatal("Enabled() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestRegistrationsMatchToolNames(t *testing.T) {
+	deps := map[reflect.Type]reflect.Value{
+		reflect.TypeOf((*client.Client)(nil)).Elem(): reflect.ValueOf(&testutil.MockClient{}),
+		reflect.TypeOf(config.LimitsConfig{}):        reflect.ValueOf(config.DefaultLimits()),
+		reflect.TypeOf(config.WritesConfig{}):        reflect.ValueOf(config.WritesConfig{}),
+		reflect.TypeOf(&config.Config{}):             reflect.ValueOf(&config.Config{}),
+	}
+
+	for _, r := range Registrations() {
+		ctor := reflect.ValueOf(r.Constructor)
+		args := make([]reflect.Value, ctor.Type().NumIn())
+		for i := range args {
+			dep, ok := deps[ctor.Type().In(i)]
+			if !ok {
+				t.Fatalf("%s: no test value for constructor parameter %s", r.Name, ctor.Type().In(i))
+			}
+			args[i] = dep
+		}
+		tool := ctor.Call(args)[0].Interface().(fxctx.Tool)
+		if got := tool.GetMcpTool().Name; got != r.Name {
+			t.Errorf("registration %q builds tool %q", r.Name, got)
+		}
+	}
+}
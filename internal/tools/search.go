@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/entity"
 	"tp-mcp-go/internal/domain/query"
 
@@ -12,7 +13,10 @@ import (
 	"github.com/strowk/foxy-contexts/pkg/mcp"
 )
 
-// entityTypeStrings converts ValidTypes to a slice of interface{} for schema enum
+// entityTypeStrings converts ValidTypes to a slice of interface{} for schema
+// enum. The schema stays fixed, since the server does not notify clients of
+// tool list changes; the types registered from TP's metadata are accepted
+// as well (see entity.ParseType).
 func entityTypeStrings() []interface{} {
 	types := make([]interface{}, len(entity.ValidTypes))
 	for i, t := range entity.ValidTypes {
@@ -22,8 +26,8 @@ func entityTypeStrings() []interface{} {
 }
 
 // NewSearchTool creates a search tool using Foxy Contexts DI
-func NewSearchTool(c client.Client) fxctx.Tool {
-	return fxctx.NewTool(
+func NewSearchTool(c client.Client, limits config.LimitsConfig) fxctx.Tool {
+	return newTool(
 		&mcp.Tool{
 			Name: "search",
 			Description: ptr("Search Target Process entities by type with optional filters. " +
@@ -89,12 +93,7 @@ func NewSearchTool(c client.Client) fxctx.Tool {
 							"type": "string",
 						},
 					},
-					"take": {
-						"type":        "integer",
-						"description": "Number of items to return (default: 100, min: 1, max: 1000)",
-						"minimum":     1,
-						"maximum":     1000,
-					},
+					"take": takeSchema("items", limits.SearchTake),
 					"orderByField": {
 						"type":        "string",
 						"description": "Field to sort results by (e.g., 'CreateDate', 'Name', 'Priority.Id'). Only one sort field is supported per request.",
@@ -112,7 +111,7 @@ func NewSearchTool(c client.Client) fxctx.Tool {
 				Required: []string{"type"},
 			},
 		},
-		func(args map[string]interface{}) *mcp.CallToolResult {
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
 			// Parse and validate entity type
 			typeStr := getStringArg(args, "type")
 			if typeStr == "" {
@@ -135,19 +134,7 @@ func NewSearchTool(c client.Client) fxctx.Tool {
 
 			// If cursor is provided, ignore all other filter params
 			if cursor == "" {
-				// Parse take with default and clamping
-				take := 100 // default
-				if t, err := getIntArg(args, "take"); err == nil {
-					take = t
-				}
-				// Clamp to [1, 1000]
-				if take < 1 {
-					take = 1
-				}
-				if take > 1000 {
-					take = 1000
-				}
-				req.Take = take
+				req.Take = getTakeArg(args, limits.SearchTake)
 
 				// Parse filters
 				req.Filters = query.SearchFilters{
@@ -177,7 +164,7 @@ func NewSearchTool(c client.Client) fxctx.Tool {
 			}
 
 			// Call client
-			resp, err := c.SearchEntities(context.Background(), req)
+			resp, err := c.SearchEntities(ctx, req)
 			if err != nil {
 				return errorResult(err)
 			}
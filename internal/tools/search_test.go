@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"tp-mcp-go/internal/config"
 	"tp-mcp-go/internal/domain/query"
 	"tp-mcp-go/internal/testutil"
 
@@ -19,7 +20,7 @@ func TestSearchToolReturnsResults(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
 	})
@@ -61,7 +62,7 @@ func TestSearchToolHandlesError(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
 	})
@@ -94,7 +95,7 @@ func TestSearchToolClampsTake(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 
 	// Test take too high
 	tool.Callback(map[string]interface{}{
@@ -132,6 +133,39 @@ func TestSearchToolClampsTake(t *testing.T) {
 	}
 }
 
+func TestSearchToolConfiguredTakeLimits(t *testing.T) {
+	var capturedReq query.SearchRequest
+	mock := &testutil.MockClient{
+		SearchEntitiesFn: func(ctx context.Context, req query.SearchRequest) (*query.PaginatedResponse, error) {
+			capturedReq = req
+			return testutil.NewSearchResponse(0), nil
+		},
+	}
+
+	limits := config.DefaultLimits()
+	limits.SearchTake = config.TakeLimit{Default: 20, Max: 50}
+	tool := NewSearchTool(mock, limits)
+
+	if got := tool.GetMcpTool().InputSchema.Properties["take"]["maximum"]; got != 50 {
+		t.Errorf("expected schema maximum 50, got %v", got)
+	}
+
+	tool.Callback(map[string]interface{}{
+		"type": "UserStory",
+		"take": float64(500),
+	})
+	if capturedReq.Take != 50 {
+		t.Errorf("expected take clamped to 50, got %d", capturedReq.Take)
+	}
+
+	tool.Callback(map[string]interface{}{
+		"type": "UserStory",
+	})
+	if capturedReq.Take != 20 {
+		t.Errorf("expected default take = 20, got %d", capturedReq.Take)
+	}
+}
+
 func TestSearchToolPaginationMetadata(t *testing.T) {
 	mock := &testutil.MockClient{
 		SearchEntitiesFn: func(ctx context.Context, req query.SearchRequest) (*query.PaginatedResponse, error) {
@@ -142,7 +176,7 @@ func TestSearchToolPaginationMetadata(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
 	})
@@ -179,7 +213,7 @@ func TestSearchToolPaginationMetadata(t *testing.T) {
 
 func TestSearchToolInvalidType(t *testing.T) {
 	mock := &testutil.MockClient{}
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type": "InvalidType",
 	})
@@ -208,7 +242,7 @@ func TestSearchToolWithFilters(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":         "UserStory",
 		"status":       "Open",
@@ -258,7 +292,7 @@ func TestSearchToolWithCursor(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":   "UserStory",
 		"cursor": "https://example.com/api/v1/UserStorys?next=abc",
@@ -289,7 +323,7 @@ func TestSearchToolOrderByFieldAlone(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":         "UserStory",
 		"orderByField": "CreateDate",
@@ -317,7 +351,7 @@ func TestSearchToolOrderByFieldWithDesc(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":             "UserStory",
 		"orderByField":     "Name",
@@ -346,7 +380,7 @@ func TestSearchToolOrderByFieldWithAsc(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":             "UserStory",
 		"orderByField":     "Priority.Id",
@@ -369,7 +403,7 @@ func TestSearchToolOrderByFieldWithAsc(t *testing.T) {
 func TestSearchToolOrderByDirectionWithoutField(t *testing.T) {
 	mock := &testutil.MockClient{}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":             "UserStory",
 		"orderByDirection": "desc",
@@ -402,7 +436,7 @@ func TestSearchToolNoOrderByParams(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type": "UserStory",
 	})
@@ -429,7 +463,7 @@ func TestSearchToolOldStyleOrderByArrayIgnored(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":    "UserStory",
 		"orderBy": []interface{}{"CreateDate desc", "Name"},
@@ -453,7 +487,7 @@ func TestSearchToolWithAssignedUserID(t *testing.T) {
 		},
 	}
 
-	tool := NewSearchTool(mock)
+	tool := NewSearchTool(mock, config.DefaultLimits())
 	result := tool.Callback(map[string]interface{}{
 		"type":         "UserStory",
 		"assignedUser": float64(789),
@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// teamAssignmentType is the resource holding an entity's state in the
+// workflow of a team
+const teamAssignmentType entity.Type = "TeamAssignment"
+
+// stateChange is the result of change_state
+type stateChange struct {
+	ID            int    `json:"id"`
+	PreviousState string `json:"previousState"`
+	State         string `json:"state"`
+	// Team names the team whose workflow state changed, for team states
+	Team    string          `json:"team,omitempty"`
+	Comment *entity.Comment `json:"comment,omitempty"`
+	// CommentError reports a comment that failed after the state changed
+	CommentError string `json:"commentError,omitempty"`
+}
+
+// stateChangePreview is the result of change_state in dry-run mode
+type stateChangePreview struct {
+	dryRunPreview
+	// CommentRequest is the request that would add the comment
+	CommentRequest *client.WriteRequest `json:"commentRequest,omitempty"`
+}
+
+// NewChangeStateTool creates a tool to move an entity to a workflow state by name
+func NewChangeStateTool(c client.Client, writes config.WritesConfig) fxctx.Tool {
+	return newTool(
+		&mcp.Tool{
+			Name: "change_state",
+			Description: ptr("Move a Target Process entity to another workflow state by name (e.g., \"In Progress\"). " +
+				"The name is resolved against the workflow of the entity's responsible team, when it has one, " +
+				"then against the workflow of the entity's project process; transitions the workflow does not " +
+				"allow are rejected. Optionally adds a comment explaining the change. " +
+				"Returns the previous and resulting state."),
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]map[string]interface{}{
+					"type": {
+						"type":        "string",
+						"description": "Entity type (e.g., UserStory, Bug, Task, Feature)",
+						"enum":        entityTypeStrings(),
+					},
+					"id": {
+						"type":        "integer",
+						"description": "Entity ID",
+					},
+					"state": {
+						"type":        "string",
+						"description": "Name of the state to move to, case-insensitive (e.g., \"In Progress\", \"Done\"); may be a state of the team workflow",
+					},
+					"comment": {
+						"type":        "string",
+						"description": "Comment to add to the entity explaining the transition",
+					},
+					"dryRun": dryRunSchema(),
+				},
+				Required: []string{"type", "id", "state"},
+			},
+		},
+		func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			typeStr := getStringArg(args, "type")
+			if typeStr == "" {
+				return errorResult(fmt.Errorf("type parameter is required"))
+			}
+			entityType, err := entity.ParseType(typeStr)
+			if err != nil {
+				return errorResult(err)
+			}
+			id, err := getIntArg(args, "id")
+			if err != nil {
+				return errorResult(err)
+			}
+			stateName := getStringArg(args, "state")
+			if stateName == "" {
+				return errorResult(fmt.Errorf("state parameter is required"))
+			}
+
+			current, err := c.GetEntity(client.WithoutCache(ctx), entityType, id,
+				[]string{"EntityState", "Project[Process]", "TeamEntityState[Name,Workflow]", "ResponsibleTeam[Team]"})
+			if err != nil {
+				return errorResult(err)
+			}
+			project, _ := current["Project"].(map[string]any)
+			process, _ := project["Process"].(map[string]any)
+			processID, err := getIntArg(process, "Id")
+			if err != nil {
+				return errorResult(fmt.Errorf("cannot determine the process of %s %d from its project", entityType, id))
+			}
+			states, err := c.GetEntityStates(ctx, entityType, processID)
+			if err != nil {
+				return errorResult(err)
+			}
+
+			// A state of the responsible team's workflow is set on its team
+			// assignment rather than on the entity
+			targetType, targetID := entityType, id
+			currentState, _ := current["EntityState"].(map[string]any)
+			var team string
+			teamState, _ := current["TeamEntityState"].(map[string]any)
+			workflow, _ := teamState["Workflow"].(map[string]any)
+			workflowID, _ := getIntArg(workflow, "Id")
+			target, isTeamState := entity.FindTeamState(states, workflowID, stateName)
+			if isTeamState {
+				responsible, _ := current["ResponsibleTeam"].(map[string]any)
+				assignmentID, err := getIntArg(responsible, "Id")
+				if err != nil {
+					return errorResult(fmt.Errorf("cannot determine the team assignment of %s %d", entityType, id))
+				}
+				targetType, targetID, currentState = teamAssignmentType, assignmentID, teamState
+				teamRef, _ := responsible["Team"].(map[string]any)
+				team, _ = teamRef["Name"].(string)
+			} else if target, err = entity.FindState(states, stateName); err != nil {
+				return errorResult(err)
+			}
+			currentID, _ := getIntArg(currentState, "Id")
+			if err := entity.CheckTransition(states, currentID, target); err != nil {
+				return errorResult(err)
+			}
+
+			fields := map[string]any{"EntityState": map[string]any{"Id": target.ID}}
+			comment := getStringArg(args, "comment")
+			if isDryRun(args, writes) {
+				req, err := c.PreviewUpdateEntity(ctx, targetType, targetID, fields)
+				if err != nil {
+					return errorResult(err)
+				}
+				preview := stateChangePreview{dryRunPreview: dryRunPreview{
+					DryRun:  true,
+					Request: req,
+					Diff:    diffFields(map[string]any{"EntityState": currentState}, fields),
+				}}
+				if comment != "" {
+					if preview.CommentRequest, err = c.PreviewCreateComment(ctx, id, comment); err != nil {
+						return errorResult(err)
+					}
+				}
+				return jsonResult(preview)
+			}
+
+			result, err := c.UpdateEntity(ctx, targetType, targetID, fields)
+			if err != nil {
+				return errorResult(err)
+			}
+			change := stateChange{ID: id, State: target.Name, Team: team}
+			change.PreviousState, _ = currentState["Name"].(string)
+			if newState, ok := result["EntityState"].(map[string]any); ok {
+				if name, ok := newState["Name"].(string); ok {
+					change.State = name
+				}
+			}
+
+			if comment != "" {
+				created, err := c.CreateComment(ctx, id, comment)
+				if err != nil {
+					change.CommentError = err.Error()
+				} else {
+					change.Comment = created
+				}
+			}
+			return jsonResult(change)
+		},
+	)
+}
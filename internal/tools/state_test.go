@@ -0,0 +1,216 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"tp-mcp-go/internal/config"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/testutil"
+
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// stateMock serves a bug in state Open of process 3, whose workflow goes
+// Open -> In Progress -> Done
+func stateMock(updated *map[string]any) *testutil.MockClient {
+	return &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{
+				"Id":          float64(id),
+				"EntityState": map[string]any{"Id": float64(1), "Name": "Open"},
+				"Project":     map[string]any{"Id": float64(12), "Process": map[string]any{"Id": float64(3)}},
+			}, nil
+		},
+		GetEntityStatesFn: func(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error) {
+			if processID != 3 {
+				return nil, errors.New("wrong process")
+			}
+			return []entity.WorkflowState{
+				{ID: 1, Name: "Open", NextStates: []int{2}},
+				{ID: 2, Name: "In Progress", NextStates: []int{3}},
+				{ID: 3, Name: "Done", NextStates: []int{}},
+			}, nil
+		},
+		UpdateEntityFn: func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+			*updated = data
+			return map[string]any{"Id": float64(id), "EntityState": map[string]any{"Id": float64(2), "Name": "In Progress"}}, nil
+		},
+	}
+}
+
+func TestChangeStateTool_MovesByName(t *testing.T) {
+	var updated map[string]any
+	var commented string
+	mock := stateMock(&updated)
+	mock.CreateCommentFn = func(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
+		commented = description
+		return &entity.Comment{ID: 77, Description: description}, nil
+	}
+
+	tool := NewChangeStateTool(mock, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{
+		"type":    "Bug",
+		"id":      float64(42),
+		"state":   "in progress",
+		"comment": "Picked up",
+	})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if ref, _ := updated["EntityState"].(map[string]any); ref["Id"] != 2 {
+		t.Errorf("expected EntityState {Id: 2} to be sent, got %v", updated)
+	}
+	var change stateChange
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &change); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if change.PreviousState != "Open" || change.State != "In Progress" || change.Comment == nil || change.Comment.ID != 77 {
+		t.Errorf("unexpected result: %+v", change)
+	}
+	if commented != "Picked up" {
+		t.Errorf("expected the comment to be added, got %q", commented)
+	}
+}
+
+func TestChangeStateTool_RejectsDisallowedTransition(t *testing.T) {
+	var updated map[string]any
+	tool := NewChangeStateTool(stateMock(&updated), config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "state": "Done"})
+
+	if result.IsError == nil || !*result.IsError {
+		t.Fatal("expected an error")
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, `does not allow moving from "Open" to "Done" (allowed: In Progress)`) {
+		t.Errorf("unexpected error: %s", text)
+	}
+	if updated != nil {
+		t.Errorf("expected nothing to be sent, got %v", updated)
+	}
+}
+
+func TestChangeStateTool_CommentFailureKeepsStateChange(t *testing.T) {
+	var updated map[string]any
+	mock := stateMock(&updated)
+	mock.CreateCommentFn = func(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
+		return nil, errors.New("comment rejected")
+	}
+
+	tool := NewChangeStateTool(mock, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "state": "In Progress", "comment": "Why"})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var change stateChange
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &change); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if change.State != "In Progress" || change.CommentError != "comment rejected" {
+		t.Errorf("unexpected result: %+v", change)
+	}
+}
+
+func TestChangeStateTool_DryRun(t *testing.T) {
+	var updated map[string]any
+	tool := NewChangeStateTool(stateMock(&updated), config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "state": "In Progress", "dryRun": true})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var preview dryRunPreview
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !preview.DryRun || preview.Diff["EntityState"].Current == nil {
+		t.Errorf("expected a dry run with a state diff, got %+v", preview)
+	}
+	if updated != nil {
+		t.Errorf("expected nothing to be sent, got %v", updated)
+	}
+}
+
+func TestChangeStateTool_MovesTeamState(t *testing.T) {
+	var updatedType entity.Type
+	var updatedID int
+	var updated map[string]any
+	mock := &testutil.MockClient{
+		GetEntityFn: func(ctx context.Context, entityType entity.Type, id int, include []string) (map[string]any, error) {
+			return map[string]any{
+				"Id":              float64(id),
+				"EntityState":     map[string]any{"Id": float64(2), "Name": "In Progress"},
+				"Project":         map[string]any{"Id": float64(12), "Process": map[string]any{"Id": float64(3)}},
+				"TeamEntityState": map[string]any{"Id": float64(20), "Name": "Coding", "Workflow": map[string]any{"Id": float64(9)}},
+				"ResponsibleTeam": map[string]any{"Id": float64(40), "Team": map[string]any{"Id": float64(5), "Name": "Alpha"}},
+			}, nil
+		},
+		GetEntityStatesFn: func(ctx context.Context, entityType entity.Type, processID int) ([]entity.WorkflowState, error) {
+			return []entity.WorkflowState{
+				{ID: 1, Name: "Open", NextStates: []int{2}},
+				{ID: 2, Name: "In Progress", NextStates: []int{3}},
+				{ID: 3, Name: "Done", NextStates: []int{}},
+				{ID: 20, Name: "Coding", ParentID: 2, WorkflowID: 9, NextStates: []int{21}},
+				{ID: 21, Name: "Code Review", ParentID: 2, WorkflowID: 9, NextStates: []int{}},
+				{ID: 30, Name: "Code Review", ParentID: 2, WorkflowID: 8, NextStates: []int{}},
+			}, nil
+		},
+		UpdateEntityFn: func(ctx context.Context, entityType entity.Type, id int, data map[string]any) (map[string]any, error) {
+			updatedType, updatedID, updated = entityType, id, data
+			return map[string]any{"Id": float64(id), "EntityState": map[string]any{"Id": float64(21), "Name": "Code Review"}}, nil
+		},
+	}
+
+	tool := NewChangeStateTool(mock, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{"type": "UserStory", "id": float64(42), "state": "code review"})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if ref, _ := updated["EntityState"].(map[string]any); updatedType != "TeamAssignment" || updatedID != 40 || ref["Id"] != 21 {
+		t.Errorf("expected team assignment 40 to move to state 21, got %s %d %v", updatedType, updatedID, updated)
+	}
+	var change stateChange
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &change); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if change.PreviousState != "Coding" || change.State != "Code Review" || change.Team != "Alpha" {
+		t.Errorf("unexpected result: %+v", change)
+	}
+
+	// Process states still apply to the entity itself
+	updated = nil
+	result = tool.Callback(map[string]interface{}{"type": "UserStory", "id": float64(42), "state": "Done"})
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	if updatedType != entity.TypeUserStory || updatedID != 42 {
+		t.Errorf("expected the entity itself to be updated, got %s %d", updatedType, updatedID)
+	}
+}
+
+func TestChangeStateTool_DryRunPreviewsComment(t *testing.T) {
+	var updated map[string]any
+	mock := stateMock(&updated)
+	mock.CreateCommentFn = func(ctx context.Context, entityID int, description string) (*entity.Comment, error) {
+		t.Error("expected no comment to be created")
+		return nil, nil
+	}
+	tool := NewChangeStateTool(mock, config.WritesConfig{})
+	result := tool.Callback(map[string]interface{}{"type": "Bug", "id": float64(42), "state": "In Progress", "comment": "Picked up", "dryRun": true})
+
+	if result.IsError != nil && *result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+	var preview stateChangePreview
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &preview); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if preview.CommentRequest == nil || preview.CommentRequest.Method != "POST" {
+		t.Errorf("expected the comment request to be previewed, got %+v", preview)
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"tp-mcp-go/internal/audit"
+	"tp-mcp-go/internal/client"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+// ContextTool is a tool whose handler receives the context of the MCP call.
+// Transports that track sessions call CallWithContext; Callback runs the
+// handler with the base context set by Bind for transports that do not.
+type ContextTool interface {
+	fxctx.Tool
+	CallWithContext(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult
+}
+
+// CallOptions apply to every call of the tools they are bound to
+type CallOptions struct {
+	// Base is the context Callback runs handlers with. Its cancellation,
+	// typically on server shutdown, also ends calls made through CallWithContext.
+	Base context.Context
+	// Timeout bounds each call; zero means no deadline
+	Timeout time.Duration
+}
+
+// Bind applies opts to every tool in ts that was built by this package
+func Bind(ts []fxctx.Tool, opts CallOptions) {
+	if opts.Base == nil {
+		opts.Base = context.Background()
+	}
+	for _, t := range ts {
+		if ct, ok := t.(*contextTool); ok {
+			ct.opts = opts
+		}
+	}
+}
+
+// toolHandler is the callback signature shared by every tool in this package
+type toolHandler func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult
+
+type contextTool struct {
+	mcpTool *mcp.Tool
+	handler toolHandler
+	opts    CallOptions
+}
+
+// newTool creates a ContextTool, the context-aware counterpart of fxctx.NewTool.
+// Every tool accepts an optional "instance" argument that routes its client
+// calls to the named Target Process instance. A tool whose schema declares
+// noCacheSchema's "noCache" argument bypasses the response cache when it is set.
+func newTool(mcpTool *mcp.Tool, handler toolHandler) ContextTool {
+	if mcpTool.InputSchema.Properties == nil {
+		mcpTool.InputSchema.Properties = map[string]map[string]interface{}{}
+	}
+	mcpTool.InputSchema.Properties["instance"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Target Process instance to use (see inspect_object list_instances). Defaults to the default instance.",
+	}
+	return newLocalTool(mcpTool, handler)
+}
+
+// newLocalTool creates a ContextTool like newTool for a tool that never
+// calls Target Process, and so takes no "instance" argument
+func newLocalTool(mcpTool *mcp.Tool, handler toolHandler) ContextTool {
+	return &contextTool{
+		mcpTool: mcpTool,
+		handler: func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+			ctx = audit.WithTool(ctx, mcpTool.Name)
+			if name := getStringArg(args, "instance"); name != "" {
+				ctx = client.WithInstance(ctx, name)
+			}
+			if getBoolArg(args, "noCache") {
+				ctx = client.WithoutCache(ctx)
+			}
+			return handler(ctx, args)
+		},
+		opts: CallOptions{Base: context.Background()},
+	}
+}
+
+func (t *contextTool) GetMcpTool() *mcp.Tool {
+	return t.mcpTool
+}
+
+func (t *contextTool) Callback(args map[string]interface{}) *mcp.CallToolResult {
+	return t.call(t.opts.Base, args)
+}
+
+func (t *contextTool) CallWithContext(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(t.opts.Base, cancel)
+	defer stop()
+	return t.call(ctx, args)
+}
+
+// call runs the handler under the configured deadline
+func (t *contextTool) call(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+	if t.opts.Timeout <= 0 {
+		return t.handler(ctx, args)
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.opts.Timeout)
+	defer cancel()
+	res := t.handler(ctx, args)
+	// Name the deadline instead of surfacing whichever request it interrupted
+	if res.IsError != nil && *res.IsError && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errorResult(fmt.Errorf("%s did not finish within %s", t.mcpTool.Name, t.opts.Timeout))
+	}
+	return res
+}
@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"tp-mcp-go/internal/client"
+	"tp-mcp-go/internal/domain/entity"
+	"tp-mcp-go/internal/testutil"
+
+	fxctx "github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+)
+
+type ctxKey struct{}
+
+func TestNewTool_PassesContext(t *testing.T) {
+	var got any
+	tool := newTool(&mcp.Tool{Name: "probe"}, func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+		got = ctx.Value(ctxKey{})
+		return textResult("ok")
+	})
+
+	tool.CallWithContext(context.WithValue(context.Background(), ctxKey{}, "session"), nil)
+	if got != "session" {
+		t.Errorf("CallWithContext: handler saw %v, want the caller's context", got)
+	}
+
+	tool.Callback(nil)
+	if got != nil {
+		t.Errorf("Callback: handler saw %v, want a background context", got)
+	}
+}
+
+func TestNewTool_RoutesInstanceArgument(t *testing.T) {
+	var got string
+	tool := newTool(&mcp.Tool{Name: "probe"}, func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+		got, _ = client.InstanceFromContext(ctx)
+		return textResult("ok")
+	})
+
+	if _, ok := tool.GetMcpTool().InputSchema.Properties["instance"]; !ok {
+		t.Error("expected every tool to accept an instance argument")
+	}
+
+	tool.Callback(map[string]interface{}{"instance": "sandbox"})
+	if got != "sandbox" {
+		t.Errorf("handler saw instance %q, want %q", got, "sandbox")
+	}
+}
+
+func TestBind_AppliesTimeout(t *testing.T) {
+	var deadline time.Time
+	tool := newTool(&mcp.Tool{Name: "probe"}, func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+		deadline, _ = ctx.Deadline()
+		<-ctx.Done()
+		return errorResult(ctx.Err())
+	})
+	Bind([]fxctx.Tool{tool}, CallOptions{Timeout: 10 * time.Millisecond})
+
+	res := tool.CallWithContext(context.Background(), nil)
+	if deadline.IsZero() {
+		t.Fatal("expected the handler context to carry a deadline")
+	}
+	if res.IsError == nil || !*res.IsError {
+		t.Fatal("expected an error result")
+	}
+	text := res.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(text, "probe did not finish within 10ms") {
+		t.Errorf("error = %q, want it to name the deadline", text)
+	}
+}
+
+func TestBind_BaseCancellationEndsCalls(t *testing.T) {
+	base, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	tool := newTool(&mcp.Tool{Name: "probe"}, func(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+		close(started)
+		<-ctx.Done()
+		return errorResult(ctx.Err())
+	})
+	Bind([]fxctx.Tool{tool}, CallOptions{Base: base})
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	done := make(chan *mcp.CallToolResult)
+	go func() { done <- tool.CallWithContext(context.Background(), nil) }()
+
+	select {
+	case res := <-done:
+		if res.IsError == nil || !*res.IsError {
+			t.Error("expected an error result")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("call was not cancelled with the base context")
+	}
+}
+
+func TestNewTool_AcceptsRegisteredEntityTypes(t *testing.T) {
+	tool := NewGetEntityTool(&testutil.MockClient{})
+	before := tool.GetMcpTool().InputSchema.Properties["type"]["enum"].([]interface{})
+
+	t.Cleanup(entity.ResetTypesForTest)
+	entity.RegisterTypes([]string{"Objective"})
+
+	// The schema does not change while clients may have listed it
+	after := tool.GetMcpTool().InputSchema.Properties["type"]["enum"].([]interface{})
+	if !slices.Equal(before, after) {
+		t.Errorf("expected the entity type enum to stay fixed, got %v then %v", before, after)
+	}
+	result := tool.Callback(map[string]interface{}{"type": "Objective", "id": float64(1)})
+	if result.IsError != nil && *result.IsError {
+		t.Errorf("expected the registered custom type to be accepted, got %v", result.Content)
+	}
+}
@@ -0,0 +1,393 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	foxyevent "github.com/strowk/foxy-contexts/pkg/foxy_event"
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/jsonrpc2"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+	"github.com/strowk/foxy-contexts/pkg/server"
+)
+
+const (
+	// sessionHeader carries the Streamable HTTP session ID
+	sessionHeader = "Mcp-Session-Id"
+
+	// maxMessageSize caps a single POSTed JSON-RPC message or batch
+	maxMessageSize = 4 << 20
+)
+
+// HTTPOptions configures the HTTP transport
+type HTTPOptions struct {
+	// Addr is the listen address, e.g. "127.0.0.1:8080"
+	Addr string
+	// BearerToken, when set, is required in the Authorization header of every MCP request
+	BearerToken string
+	// SessionIdleTimeout, when positive, ends Streamable HTTP sessions that
+	// no request has used for that long. Clients that never send DELETE
+	// would otherwise keep their sessions, and each session's TP client,
+	// until shutdown.
+	SessionIdleTimeout time.Duration
+	// SessionContext, when set, derives the context of a new session from the
+	// request that opens it (initialize, or the SSE connect). The returned
+	// context is passed to context-aware tools for the lifetime of the session;
+	// an error rejects the session with 401 Unauthorized.
+	SessionContext func(ctx context.Context, r *http.Request) (context.Context, error)
+}
+
+// HTTPTransport serves MCP over Streamable HTTP at /mcp and over the
+// legacy HTTP+SSE transport at /sse and /message.
+type HTTPTransport struct {
+	opts  HTTPOptions
+	tools []fxctx.Tool
+
+	mu       sync.Mutex
+	sessions map[string]*session
+	srv      *http.Server
+
+	closing      chan struct{}
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// NewHTTPTransport creates a server.Transport that listens on opts.Addr
+func NewHTTPTransport(opts HTTPOptions) *HTTPTransport {
+	return &HTTPTransport{
+		opts:     opts,
+		sessions: map[string]*session{},
+		closing:  make(chan struct{}),
+	}
+}
+
+// UseTools serves tools/call from tools so that context-aware tools receive
+// the session context. It must be called before Run; without it tools are
+// dispatched by the foxy-contexts tool mux and run without a session context.
+func (t *HTTPTransport) UseTools(tools []fxctx.Tool) {
+	t.tools = tools
+}
+
+func (t *HTTPTransport) Run(
+	capabilities *mcp.ServerCapabilities,
+	serverInfo *mcp.Implementation,
+	options ...server.ServerOption,
+) error {
+	ln, err := net.Listen("tcp", t.opts.Addr)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	select {
+	case <-t.closing:
+		t.mu.Unlock()
+		ln.Close()
+		return nil
+	default:
+	}
+	t.srv = &http.Server{
+		Handler:           t.handler(capabilities, serverInfo, options...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srv := t.srv
+	t.mu.Unlock()
+
+	if t.opts.SessionIdleTimeout > 0 {
+		go t.sweepIdleSessions(t.opts.SessionIdleTimeout)
+	}
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown ends all sessions and drains in-flight requests until ctx expires.
+// It is safe to call more than once.
+func (t *HTTPTransport) Shutdown(ctx context.Context) error {
+	t.shutdownOnce.Do(func() {
+		t.mu.Lock()
+		close(t.closing)
+		for id, s := range t.sessions {
+			s.close()
+			delete(t.sessions, id)
+		}
+		srv := t.srv
+		t.mu.Unlock()
+
+		if srv != nil {
+			t.shutdownErr = srv.Shutdown(ctx)
+		}
+	})
+	return t.shutdownErr
+}
+
+// handler builds the HTTP routes for the transport
+func (t *HTTPTransport) handler(capabilities *mcp.ServerCapabilities, serverInfo *mcp.Implementation, options ...server.ServerOption) http.Handler {
+	openSession := func(w http.ResponseWriter, r *http.Request, withEvents bool) (*session, bool) {
+		ctx, cancel := context.WithCancel(context.Background())
+		if t.opts.SessionContext != nil {
+			sessCtx, err := t.opts.SessionContext(ctx, r)
+			if err != nil {
+				cancel()
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return nil, false
+			}
+			ctx = sessCtx
+		}
+		srv := newSessionServer(ctx, t.tools, capabilities, serverInfo, options...)
+		sess := newSession(ctx, cancel, srv, withEvents)
+		if !t.addSession(sess) {
+			sess.close()
+			http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+			return nil, false
+		}
+		return sess, true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "ok\n")
+	})
+	mux.Handle("/mcp", t.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			t.handleStreamablePost(w, r, openSession)
+		case http.MethodDelete:
+			t.handleStreamableDelete(w, r)
+		default:
+			// No server-initiated messages are sent, so there is no GET stream.
+			w.Header().Set("Allow", "POST, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+	mux.Handle("GET /sse", t.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.handleSSEStream(w, r, openSession)
+	})))
+	mux.Handle("POST /message", t.protect(http.HandlerFunc(t.handleSSEMessage)))
+	return mux
+}
+
+// protect enforces the bearer token and rejects cross-origin browser requests
+func (t *HTTPTransport) protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != r.Host {
+				http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		if t.opts.BearerToken != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(t.opts.BearerToken)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="tp-mcp-go"`)
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// envelope holds the fields needed to route a JSON-RPC message
+type envelope struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// splitMessages returns the messages of a single JSON-RPC message or batch
+func splitMessages(body []byte) ([]json.RawMessage, bool, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			return nil, true, err
+		}
+		return batch, true, nil
+	}
+	return []json.RawMessage{trimmed}, false, nil
+}
+
+// sessionFactory opens a session for a request, writing the error reply itself when it cannot
+type sessionFactory func(w http.ResponseWriter, r *http.Request, withEvents bool) (*session, bool)
+
+func (t *HTTPTransport) handleStreamablePost(w http.ResponseWriter, r *http.Request, openSession sessionFactory) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	msgs, isBatch, err := splitMessages(body)
+	if err != nil || len(msgs) == 0 {
+		writeJSONRPCError(w, -32700, "Parse error")
+		return
+	}
+
+	envs := make([]envelope, len(msgs))
+	initializing := false
+	for i, m := range msgs {
+		if err := json.Unmarshal(m, &envs[i]); err != nil {
+			writeJSONRPCError(w, -32700, "Parse error")
+			return
+		}
+		if envs[i].Method == "initialize" {
+			initializing = true
+		}
+	}
+
+	var sess *session
+	if initializing {
+		if isBatch && len(msgs) > 1 {
+			writeJSONRPCError(w, -32600, "initialize must not be part of a batch")
+			return
+		}
+		var ok bool
+		if sess, ok = openSession(w, r, false); !ok {
+			return
+		}
+		w.Header().Set(sessionHeader, sess.id)
+	} else {
+		id := r.Header.Get(sessionHeader)
+		if id == "" {
+			http.Error(w, "missing "+sessionHeader+" header", http.StatusBadRequest)
+			return
+		}
+		var ok bool
+		if sess, ok = t.session(id); !ok {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+	}
+	sess.acquire()
+	defer sess.release()
+
+	// Requests answered inline also end when the client drops the connection
+	ctx, cancel := context.WithCancel(sess.ctx)
+	defer cancel()
+	stop := context.AfterFunc(r.Context(), cancel)
+	defer stop()
+
+	var responses []json.RawMessage
+	for i, m := range msgs {
+		res := sess.server.handle(ctx, m)
+		// Notifications and client responses are acknowledged without a body.
+		if res == nil || len(envs[i].ID) == 0 {
+			continue
+		}
+		data, err := jsonrpc2.Marshal(res.Id, res.Result, res.Error)
+		if err != nil {
+			sess.server.GetLogger().LogEvent(foxyevent.SSEFailedMarshalEvent{Err: err})
+			continue
+		}
+		responses = append(responses, data)
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if isBatch {
+		json.NewEncoder(w).Encode(responses)
+		return
+	}
+	w.Write(responses[0])
+}
+
+func (t *HTTPTransport) handleStreamableDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		http.Error(w, "missing "+sessionHeader+" header", http.StatusBadRequest)
+		return
+	}
+	if !t.removeSession(id) {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// addSession registers a session unless the transport is shutting down
+func (t *HTTPTransport) addSession(s *session) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.closing:
+		return false
+	default:
+	}
+	t.sessions[s.id] = s
+	return true
+}
+
+func (t *HTTPTransport) session(id string) (*session, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.sessions[id]
+	return s, ok
+}
+
+// removeSession closes and forgets a session, reporting whether it existed
+func (t *HTTPTransport) removeSession(id string) bool {
+	t.mu.Lock()
+	s, ok := t.sessions[id]
+	delete(t.sessions, id)
+	t.mu.Unlock()
+	if ok {
+		s.close()
+	}
+	return ok
+}
+
+// sweepIdleSessions ends idle sessions every half timeout until shutdown
+func (t *HTTPTransport) sweepIdleSessions(timeout time.Duration) {
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.closing:
+			return
+		case now := <-ticker.C:
+			t.expireIdleSessions(now.Add(-timeout))
+		}
+	}
+}
+
+// expireIdleSessions ends the Streamable HTTP sessions last used before
+// cutoff. SSE sessions end with their stream, and a session serving a
+// request is in use however long the request takes.
+func (t *HTTPTransport) expireIdleSessions(cutoff time.Time) {
+	t.mu.Lock()
+	var expired []*session
+	for id, s := range t.sessions {
+		if s.events == nil && s.idleSince(cutoff) {
+			expired = append(expired, s)
+			delete(t.sessions, id)
+		}
+	}
+	t.mu.Unlock()
+	for _, s := range expired {
+		s.close()
+	}
+}
+
+// writeJSONRPCError replies with a JSON-RPC error that has no request ID
+func writeJSONRPCError(w http.ResponseWriter, code int, message string) {
+	data, _ := jsonrpc2.Marshal(jsonrpc2.NewNullRequestId(), nil, &jsonrpc2.Error{Code: code, Message: message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(data)
+}
@@ -0,0 +1,372 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+	"github.com/strowk/foxy-contexts/pkg/server"
+)
+
+const initializeBody = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+
+// newTestServer starts the transport's handler with a single echo tool registered
+func newTestServer(t *testing.T, opts HTTPOptions) (*HTTPTransport, *httptest.Server) {
+	t.Helper()
+	tr := NewHTTPTransport(opts)
+
+	echo := fxctx.NewTool(
+		&mcp.Tool{Name: "echo", InputSchema: mcp.ToolInputSchema{Type: "object"}},
+		func(args map[string]interface{}) *mcp.CallToolResult {
+			text, _ := args["text"].(string)
+			return &mcp.CallToolResult{Content: []interface{}{mcp.TextContent{Type: "text", Text: text}}}
+		},
+	)
+	mux := fxctx.NewToolMux([]fxctx.Tool{echo})
+
+	h := tr.handler(
+		&mcp.ServerCapabilities{Tools: &mcp.ServerCapabilitiesTools{}},
+		&mcp.Implementation{Name: "test", Version: "0"},
+		server.ServerStartCallbackOption{Callback: func(s server.Server) { mux.RegisterHandlers(s) }},
+	)
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return tr, srv
+}
+
+func post(t *testing.T, url, sessionID, bearer, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func initialize(t *testing.T, srv *httptest.Server, bearer string) string {
+	t.Helper()
+	resp := post(t, srv.URL+"/mcp", "", bearer, initializeBody)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("initialize status = %d, want 200", resp.StatusCode)
+	}
+	id := resp.Header.Get(sessionHeader)
+	if id == "" {
+		t.Fatal("initialize response missing session header")
+	}
+	return id
+}
+
+func TestStreamableHTTP_InitializeAndCallTool(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{})
+	id := initialize(t, srv, "")
+
+	resp := post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("notification status = %d, want 202", resp.StatusCode)
+	}
+
+	resp = post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hello"}}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("tools/call status = %d, want 200", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `"text":"hello"`) {
+		t.Errorf("expected echoed text in response, got %s", body)
+	}
+}
+
+func TestStreamableHTTP_NegotiatesProtocolVersion(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{})
+
+	resp := post(t, srv.URL+"/mcp", "", "", strings.Replace(initializeBody, "2025-03-26", "2024-11-05", 1))
+	var msg struct {
+		Result struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if msg.Result.ProtocolVersion != "2024-11-05" {
+		t.Errorf("protocolVersion = %q, want 2024-11-05", msg.Result.ProtocolVersion)
+	}
+}
+
+func TestStreamableHTTP_Batch(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{})
+	id := initialize(t, srv, "")
+
+	resp := post(t, srv.URL+"/mcp", id, "", `[{"jsonrpc":"2.0","id":2,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":3,"method":"tools/list"}]`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("batch status = %d, want 200", resp.StatusCode)
+	}
+	var batch []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		t.Fatalf("failed to decode batch: %v", err)
+	}
+	if len(batch) != 2 {
+		t.Errorf("expected 2 responses (notification has none), got %d", len(batch))
+	}
+}
+
+func TestStreamableHTTP_SessionErrors(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{})
+
+	resp := post(t, srv.URL+"/mcp", "", "", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing session status = %d, want 400", resp.StatusCode)
+	}
+
+	resp = post(t, srv.URL+"/mcp", "does-not-exist", "", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestStreamableHTTP_DeleteEndsSession(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{})
+	id := initialize(t, srv, "")
+
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/mcp", nil)
+	req.Header.Set(sessionHeader, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("delete status = %d, want 204", resp.StatusCode)
+	}
+
+	resp = post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("post after delete status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestStreamableHTTP_IdleSessionExpires(t *testing.T) {
+	tr, srv := newTestServer(t, HTTPOptions{SessionIdleTimeout: time.Minute})
+	id := initialize(t, srv, "")
+
+	tr.expireIdleSessions(time.Now().Add(-time.Minute))
+	resp := post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("post to a recently used session status = %d, want 200", resp.StatusCode)
+	}
+
+	tr.expireIdleSessions(time.Now().Add(time.Second))
+	resp = post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("post to an expired session status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestHTTP_BearerToken(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{BearerToken: "s3cret"})
+
+	resp := post(t, srv.URL+"/mcp", "", "", initializeBody)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("no token status = %d, want 401", resp.StatusCode)
+	}
+	resp = post(t, srv.URL+"/mcp", "", "wrong", initializeBody)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("wrong token status = %d, want 401", resp.StatusCode)
+	}
+	initialize(t, srv, "s3cret")
+
+	health, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("healthz failed: %v", err)
+	}
+	health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("healthz status = %d, want 200 without token", health.StatusCode)
+	}
+}
+
+func TestHTTP_RejectsCrossOrigin(t *testing.T) {
+	_, srv := newTestServer(t, HTTPOptions{})
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/mcp", strings.NewReader(initializeBody))
+	req.Header.Set("Origin", "https://evil.example.com")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("cross-origin status = %d, want 403", resp.StatusCode)
+	}
+}
+
+// whoamiTool reports the user its session context carries
+type whoamiTool struct{}
+
+type userKey struct{}
+
+func (whoamiTool) GetMcpTool() *mcp.Tool {
+	return &mcp.Tool{Name: "whoami", InputSchema: mcp.ToolInputSchema{Type: "object"}}
+}
+
+func (whoamiTool) Callback(args map[string]interface{}) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []interface{}{mcp.TextContent{Type: "text", Text: "nobody"}}}
+}
+
+func (whoamiTool) CallWithContext(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+	user, _ := ctx.Value(userKey{}).(string)
+	return &mcp.CallToolResult{Content: []interface{}{mcp.TextContent{Type: "text", Text: "user=" + user}}}
+}
+
+func TestHTTP_SessionContext(t *testing.T) {
+	tr, srv := newTestServer(t, HTTPOptions{
+		SessionContext: func(ctx context.Context, r *http.Request) (context.Context, error) {
+			user := r.Header.Get("X-User")
+			if user == "" {
+				return nil, errors.New("X-User header is required")
+			}
+			return context.WithValue(ctx, userKey{}, user), nil
+		},
+	})
+	tr.UseTools([]fxctx.Tool{whoamiTool{}})
+
+	resp := post(t, srv.URL+"/mcp", "", "", initializeBody)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("rejected session status = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/mcp", strings.NewReader(initializeBody))
+	req.Header.Set("X-User", "alice")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	resp.Body.Close()
+	id := resp.Header.Get(sessionHeader)
+
+	resp = post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"whoami","arguments":{}}}`)
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "user=alice") {
+		t.Errorf("expected tool to see the session context, got %s", body)
+	}
+}
+
+// blockingTool runs until its context is cancelled
+type blockingTool struct {
+	started chan struct{}
+}
+
+func (blockingTool) GetMcpTool() *mcp.Tool {
+	return &mcp.Tool{Name: "block", InputSchema: mcp.ToolInputSchema{Type: "object"}}
+}
+
+func (blockingTool) Callback(args map[string]interface{}) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []interface{}{mcp.TextContent{Type: "text", Text: "no context"}}}
+}
+
+func (b blockingTool) CallWithContext(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult {
+	close(b.started)
+	<-ctx.Done()
+	return &mcp.CallToolResult{Content: []interface{}{mcp.TextContent{Type: "text", Text: "stopped: " + ctx.Err().Error()}}}
+}
+
+func TestStreamableHTTP_CancelledNotification(t *testing.T) {
+	tr, srv := newTestServer(t, HTTPOptions{})
+	tool := blockingTool{started: make(chan struct{})}
+	tr.UseTools([]fxctx.Tool{tool})
+	id := initialize(t, srv, "")
+
+	done := make(chan string, 1)
+	go func() {
+		resp := post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"block","arguments":{}}}`)
+		body, _ := io.ReadAll(resp.Body)
+		done <- string(body)
+	}()
+
+	select {
+	case <-tool.started:
+	case <-time.After(time.Second):
+		t.Fatal("tool call did not start")
+	}
+	resp := post(t, srv.URL+"/mcp", id, "", `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1"}}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("cancel status = %d, want 202", resp.StatusCode)
+	}
+
+	select {
+	case body := <-done:
+		if !strings.Contains(body, "stopped: context canceled") || !strings.Contains(body, `"id":"call-1"`) {
+			t.Errorf("unexpected response %s", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+}
+
+func TestSSE_Roundtrip(t *testing.T) {
+	tr, srv := newTestServer(t, HTTPOptions{})
+
+	stream, err := http.Get(srv.URL + "/sse")
+	if err != nil {
+		t.Fatalf("sse connect failed: %v", err)
+	}
+	defer stream.Body.Close()
+	events := bufio.NewReader(stream.Body)
+
+	readData := func() string {
+		t.Helper()
+		for {
+			line, err := events.ReadString('\n')
+			if err != nil {
+				t.Fatalf("failed reading sse stream: %v", err)
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				return strings.TrimSpace(data)
+			}
+		}
+	}
+
+	endpoint := readData()
+	if !strings.HasPrefix(endpoint, "/message?sessionId=") {
+		t.Fatalf("unexpected endpoint event: %q", endpoint)
+	}
+
+	resp := post(t, srv.URL+endpoint, "", "", `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"echo","arguments":{"text":"via sse"}}}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("message status = %d, want 202", resp.StatusCode)
+	}
+	if msg := readData(); !strings.Contains(msg, "via sse") {
+		t.Errorf("expected tool response on stream, got %s", msg)
+	}
+
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+	if _, err := io.ReadAll(events); err != nil {
+		t.Errorf("expected stream to end cleanly after shutdown, got %v", err)
+	}
+	if err := tr.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown returned error: %v", err)
+	}
+}
@@ -0,0 +1,310 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	foxyevent "github.com/strowk/foxy-contexts/pkg/foxy_event"
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/jsonrpc2"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+	"github.com/strowk/foxy-contexts/pkg/server"
+)
+
+// supportedProtocolVersions lists the MCP revisions served over HTTP, newest first
+var supportedProtocolVersions = []string{"2025-03-26", "2024-11-05", "2024-10-07"}
+
+// sessionServer is a server.Server whose requests can also be handled
+// synchronously, so HTTP handlers can write the JSON-RPC response into the
+// reply of the request that produced it.
+type sessionServer struct {
+	router    jsonrpc2.JsonRpcRouter
+	responses chan jsonrpc2.JsonRpcResponse
+	logger    foxyevent.Logger
+
+	// ctx is the session's context, used for messages handled through Handle
+	ctx context.Context
+	// tools serves tools/call when non-nil; see handle
+	tools map[string]fxctx.Tool
+
+	// inflight cancels running tool calls by JSON-RPC request ID
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+// contextTool matches tools that accept the calling session's context (see tools.ContextTool)
+type contextTool interface {
+	CallWithContext(ctx context.Context, args map[string]interface{}) *mcp.CallToolResult
+}
+
+// newSessionServer creates a sessionServer and applies the options foxy-contexts
+// passes to Transport.Run. server.ServerOption is applied through an unexported
+// method, so only the exported option types can be honoured here; the start
+// callback is the one that registers tools, resources and prompts.
+//
+// When tools is non-empty, tools/call is served from it instead, so that
+// context-aware tools run with a context derived from ctx, the session's
+// context, and can be cancelled with notifications/cancelled.
+func newSessionServer(ctx context.Context, tools []fxctx.Tool, capabilities *mcp.ServerCapabilities, serverInfo *mcp.Implementation, options ...server.ServerOption) *sessionServer {
+	s := &sessionServer{
+		router:    jsonrpc2.NewJsonRPCRouter(),
+		responses: make(chan jsonrpc2.JsonRpcResponse),
+		logger:    foxyevent.NewSlogLogger(slog.Default()),
+		ctx:       ctx,
+		inflight:  make(map[string]context.CancelFunc),
+	}
+
+	s.SetRequestHandler(&mcp.InitializeRequest{}, func(req jsonrpc2.Request) (jsonrpc2.Result, *jsonrpc2.Error) {
+		requested := req.(*mcp.InitializeRequest).Params.ProtocolVersion
+		version := supportedProtocolVersions[0]
+		if slices.Contains(supportedProtocolVersions, requested) {
+			version = requested
+		}
+		return &mcp.InitializeResult{
+			ProtocolVersion: version,
+			Capabilities:    *capabilities,
+			ServerInfo:      *serverInfo,
+		}, nil
+	})
+	s.SetRequestHandler(&mcp.PingRequest{}, func(req jsonrpc2.Request) (jsonrpc2.Result, *jsonrpc2.Error) {
+		return struct{}{}, nil
+	})
+	s.SetNotificationHandler(&mcp.InitializedNotification{}, func(req jsonrpc2.Request) {})
+	s.SetNotificationHandler(&mcp.CancelledNotification{}, func(req jsonrpc2.Request) {})
+
+	for _, o := range options {
+		switch opt := o.(type) {
+		case server.ServerStartCallbackOption:
+			opt.Callback(s)
+		case *server.ServerStartCallbackOption:
+			opt.Callback(s)
+		case server.LoggerOption:
+			s.logger = opt.Logger
+		case *server.LoggerOption:
+			s.logger = opt.Logger
+		}
+	}
+
+	if len(tools) > 0 {
+		s.tools = make(map[string]fxctx.Tool, len(tools))
+		for _, t := range tools {
+			s.tools[t.GetMcpTool().Name] = t
+		}
+	}
+	return s
+}
+
+// message holds the fields needed to dispatch a JSON-RPC message
+type message struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// handle dispatches a single JSON-RPC message and returns its response, if
+// any. Tool calls run with a child of ctx that notifications/cancelled for
+// their request ID cancels; everything else goes through the router.
+func (s *sessionServer) handle(ctx context.Context, b []byte) *jsonrpc2.JsonRpcResponse {
+	var msg message
+	if err := json.Unmarshal(b, &msg); err == nil {
+		switch {
+		case msg.Method == "notifications/cancelled":
+			s.cancelCall(msg.Params)
+			return nil
+		case s.servesCall(msg):
+			ctx, untrack := s.track(ctx, msg.ID)
+			defer untrack()
+			return s.callTool(ctx, msg.ID, msg.Params)
+		}
+	}
+	return s.router.Handle(b)
+}
+
+// servesCall reports whether msg is a tools/call request served from s.tools
+func (s *sessionServer) servesCall(msg message) bool {
+	return msg.Method == "tools/call" && s.tools != nil && len(msg.ID) > 0
+}
+
+// track returns a child of ctx that notifications/cancelled for the request
+// with the given raw ID cancels, and a func to call once the request is done
+func (s *sessionServer) track(ctx context.Context, rawID json.RawMessage) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	key := string(rawID)
+	s.mu.Lock()
+	s.inflight[key] = cancel
+	s.mu.Unlock()
+	return ctx, func() {
+		s.mu.Lock()
+		delete(s.inflight, key)
+		s.mu.Unlock()
+		cancel()
+	}
+}
+
+// callTool runs the tools/call request with the given ID and params
+func (s *sessionServer) callTool(ctx context.Context, rawID, rawParams json.RawMessage) *jsonrpc2.JsonRpcResponse {
+	id, ok := requestID(rawID)
+	if !ok {
+		return &jsonrpc2.JsonRpcResponse{Id: jsonrpc2.NewNullRequestId(), Error: &jsonrpc2.Error{Code: -32600, Message: "Invalid Request", Data: "invalid id"}}
+	}
+	var params mcp.CallToolRequestParams
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return &jsonrpc2.JsonRpcResponse{Id: id, Error: &jsonrpc2.Error{Code: -32602, Message: "Invalid params", Data: err.Error()}}
+	}
+	tool, ok := s.tools[params.Name]
+	if !ok {
+		return &jsonrpc2.JsonRpcResponse{Id: id, Error: jsonrpc2.NewServerError(fxctx.ToolNotFound, fmt.Sprintf("tool not found: %s", params.Name))}
+	}
+
+	var res *mcp.CallToolResult
+	if ct, ok := tool.(contextTool); ok {
+		res = ct.CallWithContext(ctx, params.Arguments)
+	} else {
+		res = tool.Callback(params.Arguments)
+	}
+	var result jsonrpc2.Result = &mcp.CallToolResult{
+		Meta:    res.Meta,
+		Content: res.Content,
+		IsError: res.IsError,
+	}
+	return &jsonrpc2.JsonRpcResponse{Id: id, Result: &result}
+}
+
+// cancelCall cancels the in-flight tool call named by a notifications/cancelled message
+func (s *sessionServer) cancelCall(rawParams json.RawMessage) {
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if err := json.Unmarshal(rawParams, &params); err != nil {
+		return
+	}
+	s.mu.Lock()
+	cancel, ok := s.inflight[string(params.RequestID)]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// requestID converts a raw JSON-RPC ID, which is a string or an integer
+func requestID(raw json.RawMessage) (jsonrpc2.RequestId, bool) {
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return jsonrpc2.RequestId{IdNumber: n, IdIsNum: true}, true
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		return jsonrpc2.NewStringRequestId(str), true
+	}
+	return jsonrpc2.RequestId{}, false
+}
+
+func (s *sessionServer) Handle(b []byte) {
+	if res := s.handle(s.ctx, b); res != nil {
+		s.responses <- *res
+	}
+}
+
+func (s *sessionServer) GetResponses() chan jsonrpc2.JsonRpcResponse {
+	return s.responses
+}
+
+func (s *sessionServer) SetRequestHandler(request jsonrpc2.Request, handler func(req jsonrpc2.Request) (jsonrpc2.Result, *jsonrpc2.Error)) {
+	s.router.SetRequestHandler(request, handler)
+}
+
+func (s *sessionServer) SetNotificationHandler(request jsonrpc2.Request, handler func(req jsonrpc2.Request)) {
+	s.router.SetNotificationHandler(request, handler)
+}
+
+func (s *sessionServer) SetLogger(logger foxyevent.Logger) {
+	s.logger = logger
+}
+
+func (s *sessionServer) GetLogger() foxyevent.Logger {
+	return s.logger
+}
+
+// session is one connected MCP client
+type session struct {
+	id     string
+	server *sessionServer
+
+	// ctx lives as long as the session and carries whatever
+	// HTTPOptions.SessionContext attached to it
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// events carries encoded responses to the legacy SSE stream; nil for
+	// Streamable HTTP sessions, which reply inline.
+	events chan []byte
+
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// active counts the requests using the session and lastUsed is when the
+	// last one finished; see HTTPTransport.expireIdleSessions
+	mu       sync.Mutex
+	active   int
+	lastUsed time.Time
+}
+
+func newSession(ctx context.Context, cancel context.CancelFunc, srv *sessionServer, withEvents bool) *session {
+	s := &session{
+		id:       newSessionID(),
+		server:   srv,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		lastUsed: time.Now(),
+	}
+	if withEvents {
+		s.events = make(chan []byte, 16)
+	}
+	return s
+}
+
+// acquire marks the session as in use until the matching release
+func (s *session) acquire() {
+	s.mu.Lock()
+	s.active++
+	s.mu.Unlock()
+}
+
+func (s *session) release() {
+	s.mu.Lock()
+	s.active--
+	s.lastUsed = time.Now()
+	s.mu.Unlock()
+}
+
+// idleSince reports whether no request has used the session since cutoff
+func (s *session) idleSince(cutoff time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active == 0 && s.lastUsed.Before(cutoff)
+}
+
+// close marks the session as finished; safe to call more than once
+func (s *session) close() {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		close(s.done)
+	})
+}
+
+// newSessionID returns a random, URL-safe session identifier
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return hex.EncodeToString(b)
+}
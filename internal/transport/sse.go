@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	foxyevent "github.com/strowk/foxy-contexts/pkg/foxy_event"
+	"github.com/strowk/foxy-contexts/pkg/jsonrpc2"
+)
+
+// sseKeepAlive is the interval between comment frames on idle SSE streams
+const sseKeepAlive = 15 * time.Second
+
+// handleSSEStream serves the legacy HTTP+SSE transport: it announces the
+// message endpoint and then streams every response for the session.
+func (t *HTTPTransport) handleSSEStream(w http.ResponseWriter, r *http.Request, openSession sessionFactory) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sess, ok := openSession(w, r, true)
+	if !ok {
+		return
+	}
+	defer t.removeSession(sess.id)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	writeSSEEvent(w, "endpoint", []byte("/message?sessionId="+sess.id))
+	flusher.Flush()
+
+	logger := sess.server.GetLogger()
+	logger.LogEvent(foxyevent.SSEClientConnected{ClientIP: r.RemoteAddr})
+	defer logger.LogEvent(foxyevent.SSEClientDisconnected{ClientIP: r.RemoteAddr})
+
+	ticker := time.NewTicker(sseKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-sess.done:
+			return
+		case data := <-sess.events:
+			if err := writeSSEEvent(w, "message", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// handleSSEMessage accepts a client message for a legacy SSE session; the
+// response is delivered on the session's event stream.
+func (t *HTTPTransport) handleSSEMessage(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("sessionId")
+	if id == "" {
+		http.Error(w, "sessionId is required", http.StatusBadRequest)
+		return
+	}
+	sess, ok := t.session(id)
+	if !ok || sess.events == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		writeJSONRPCError(w, -32700, "Parse error")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	// The reply goes to the event stream, so the call is bound to the session
+	// rather than to this request, which the client may close after the 202
+	res := sess.server.handle(sess.ctx, body)
+	if res == nil || len(env.ID) == 0 {
+		return
+	}
+	data, err := jsonrpc2.Marshal(res.Id, res.Result, res.Error)
+	if err != nil {
+		sess.server.GetLogger().LogEvent(foxyevent.SSEFailedMarshalEvent{Err: err})
+		return
+	}
+	select {
+	case sess.events <- data:
+	case <-sess.done:
+	}
+}
+
+// writeSSEEvent writes one server-sent event
+func writeSSEEvent(w io.Writer, event string, data []byte) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
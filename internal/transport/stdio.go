@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+	"github.com/strowk/foxy-contexts/pkg/server"
+	"github.com/strowk/foxy-contexts/pkg/stdio"
+)
+
+// StdioTransport serves MCP over standard input and output. It reads and
+// writes messages like the foxy-contexts stdio transport, but once UseTools
+// is called it runs tool calls concurrently, so that notifications/cancelled
+// is read while a call runs and cancels it.
+type StdioTransport struct {
+	server.Transport
+	tools []fxctx.Tool
+}
+
+// NewStdioTransport creates a StdioTransport; options are passed on to the
+// foxy-contexts stdio transport, e.g. to replace stdin and stdout in tests
+func NewStdioTransport(options ...stdio.StdioTransportOption) *StdioTransport {
+	t := &StdioTransport{}
+	options = append(options, stdio.WithNewServerFunc(t.newServer))
+	t.Transport = stdio.NewTransport(options...)
+	return t
+}
+
+// UseTools serves tools/call from tools, as HTTPTransport.UseTools does.
+// It must be called before Run.
+func (t *StdioTransport) UseTools(tools []fxctx.Tool) {
+	t.tools = tools
+}
+
+func (t *StdioTransport) newServer(capabilities *mcp.ServerCapabilities, serverInfo *mcp.Implementation, options ...server.ServerOption) server.Server {
+	return &stdioServer{newSessionServer(context.Background(), t.tools, capabilities, serverInfo, options...)}
+}
+
+// stdioServer handles the messages of the single stdio session. The
+// transport hands it one message at a time, so tool calls run in their own
+// goroutine to keep the next messages, cancellations among them, flowing.
+type stdioServer struct {
+	*sessionServer
+}
+
+func (s *stdioServer) Handle(b []byte) {
+	var msg message
+	if err := json.Unmarshal(b, &msg); err != nil || !s.servesCall(msg) {
+		s.sessionServer.Handle(b)
+		return
+	}
+	// Tracked before the goroutine starts, so a cancellation read right
+	// after the call still finds it
+	ctx, untrack := s.track(s.ctx, msg.ID)
+	go func() {
+		defer untrack()
+		s.responses <- *s.callTool(ctx, msg.ID, msg.Params)
+	}()
+}
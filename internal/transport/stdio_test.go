@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strowk/foxy-contexts/pkg/fxctx"
+	"github.com/strowk/foxy-contexts/pkg/mcp"
+	"github.com/strowk/foxy-contexts/pkg/server"
+	"github.com/strowk/foxy-contexts/pkg/stdio"
+)
+
+func TestStdio_CancelledNotification(t *testing.T) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	tr := NewStdioTransport(stdio.WithIn(inR), stdio.WithOut(outW))
+	tool := blockingTool{started: make(chan struct{})}
+	tr.UseTools([]fxctx.Tool{tool})
+
+	go tr.Run(
+		&mcp.ServerCapabilities{Tools: &mcp.ServerCapabilitiesTools{}},
+		&mcp.Implementation{Name: "test", Version: "0"},
+		server.ServerStartCallbackOption{Callback: func(s server.Server) {}},
+	)
+	t.Cleanup(func() {
+		inW.Close()
+		outR.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		tr.Shutdown(ctx)
+	})
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"block","arguments":{}}}`+"\n")
+	select {
+	case <-tool.started:
+	case <-time.After(time.Second):
+		t.Fatal("tool call did not start")
+	}
+	io.WriteString(inW, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1"}}`+"\n")
+
+	line := make(chan string, 1)
+	go func() {
+		s, _ := bufio.NewReader(outR).ReadString('\n')
+		line <- s
+	}()
+	select {
+	case body := <-line:
+		if !strings.Contains(body, "stopped: context canceled") || !strings.Contains(body, `"id":"call-1"`) {
+			t.Errorf("unexpected response %s", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tool call was not cancelled")
+	}
+}